@@ -0,0 +1,80 @@
+// Package api holds the helpers the HTTP handlers share: reading the
+// authenticated user and path IDs, and turning service errors into
+// responses.
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CurrentUser returns the user RequireAuth stored in the context. Without
+// one it writes an error response and returns false.
+func CurrentUser(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	userModel, ok := user.(*models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
+		return nil, false
+	}
+
+	return userModel, true
+}
+
+// ParamID parses a UUID path parameter. If it is not one, it responds with
+// 400 and msg and returns false.
+func ParamID(c *gin.Context, param, msg string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ErrorResponse maps errors matching Err to Status. Message replaces the
+// error text in the response when set.
+type ErrorResponse struct {
+	Err     error
+	Status  int
+	Message string
+}
+
+// RespondError writes the response of the first mapping err matches.
+// validation.Errors are written as 422 with their field errors. Anything
+// else is logged and reported as a 500 with msg.
+func RespondError(c *gin.Context, logger *zap.Logger, msg string, err error, responses ...ErrorResponse) {
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		validation.Respond(c, http.StatusUnprocessableEntity, fieldErrs)
+		return
+	}
+
+	for _, response := range responses {
+		if !errors.Is(err, response.Err) {
+			continue
+		}
+		message := response.Message
+		if message == "" {
+			message = err.Error()
+		}
+		c.JSON(response.Status, gin.H{"error": message})
+		return
+	}
+
+	logger.Error(msg, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+}
+
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func newContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	return c, w
+}
+
+func errorBody(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %s", w.Body)
+	}
+	return body.Error
+}
+
+func TestCurrentUser(t *testing.T) {
+	c, w := newContext()
+	if _, ok := CurrentUser(c); ok || w.Code != http.StatusUnauthorized {
+		t.Errorf("without user: ok = %v, status %d", ok, w.Code)
+	}
+
+	c, w = newContext()
+	c.Set("user", "alice")
+	if _, ok := CurrentUser(c); ok || w.Code != http.StatusInternalServerError {
+		t.Errorf("with wrong type: ok = %v, status %d", ok, w.Code)
+	}
+
+	c, _ = newContext()
+	user := &models.User{ID: uuid.New()}
+	c.Set("user", user)
+	if got, ok := CurrentUser(c); !ok || got != user {
+		t.Errorf("CurrentUser = %v, %v", got, ok)
+	}
+}
+
+func TestParamID(t *testing.T) {
+	id := uuid.New()
+
+	c, _ := newContext()
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	if got, ok := ParamID(c, "id", "Invalid ID"); !ok || got != id {
+		t.Errorf("ParamID = %v, %v", got, ok)
+	}
+
+	c, w := newContext()
+	c.Params = gin.Params{{Key: "id", Value: "nope"}}
+	if _, ok := ParamID(c, "id", "Invalid ID"); ok || w.Code != http.StatusBadRequest || errorBody(t, w) != "Invalid ID" {
+		t.Errorf("invalid ID: ok = %v, status %d, body %s", ok, w.Code, w.Body)
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	errNotFound := errors.New("thing not found")
+	errConflict := errors.New("thing in use")
+	responses := []ErrorResponse{
+		{Err: errNotFound, Status: http.StatusNotFound, Message: "Thing not found"},
+		{Err: errConflict, Status: http.StatusConflict},
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"fixed message", errNotFound, http.StatusNotFound, "Thing not found"},
+		{"wrapped error text", fmt.Errorf("%w: 2 houses", errConflict), http.StatusConflict, "thing in use: 2 houses"},
+		{"unmapped", errors.New("connection reset"), http.StatusInternalServerError, "Failed to do it"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newContext()
+			RespondError(c, zap.NewNop(), "Failed to do it", tt.err, responses...)
+			if w.Code != tt.status || errorBody(t, w) != tt.body {
+				t.Errorf("response = %d %s, want %d %q", w.Code, w.Body, tt.status, tt.body)
+			}
+		})
+	}
+
+	c, w := newContext()
+	RespondError(c, zap.NewNop(), "Failed", fmt.Errorf("wrapped: %w", validation.Errors{{Field: "name", Rule: "required", Message: "is required"}}))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("validation errors status = %d, want 422", w.Code)
+	}
+}
+
@@ -0,0 +1,79 @@
+package attachments
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// sniffLength is how much of a file content detection looks at
+const sniffLength = 512
+
+const maxFilenameLength = 255
+
+// extensionTypes are formats that content sniffing reports only as their
+// generic container or text type
+var extensionTypes = map[string]string{
+	".csv":  "text/csv",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+}
+
+// detectContentType sniffs the type from the start of the file. The file
+// name only refines a generic result, e.g. a zip named .docx, so renaming
+// a file cannot change what it is accepted as.
+func detectContentType(head []byte, filename string) string {
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		sniffed = "application/octet-stream"
+	}
+
+	byExtension := extensionTypes[strings.ToLower(filepath.Ext(filename))]
+	switch {
+	case sniffed == "application/zip" && strings.HasPrefix(byExtension, "application/vnd."):
+		return byExtension
+	case sniffed == "text/plain" && strings.HasPrefix(byExtension, "text/"):
+		return byExtension
+	}
+	return sniffed
+}
+
+// typeAllowed matches the content type against the allowed list, where
+// "image/*" allows any image type
+func typeAllowed(allowed []string, contentType string) bool {
+	for _, pattern := range allowed {
+		if pattern == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanFilename keeps the base name of an uploaded file without control
+// characters, so it is safe to echo back in headers
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+	return name
+}
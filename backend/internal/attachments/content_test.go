@@ -0,0 +1,100 @@
+package attachments
+
+import "testing"
+
+var (
+	pngHead  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	pdfHead  = []byte("%PDF-1.7\n")
+	zipHead  = []byte("PK\x03\x04\x14\x00\x06\x00")
+	textHead = []byte("name,amount\nalpha,1\n")
+	htmlHead = []byte("<!DOCTYPE html><html><body>hi</body></html>")
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     []byte
+		filename string
+		want     string
+	}{
+		{"png", pngHead, "photo.png", "image/png"},
+		{"png named as document", pngHead, "photo.docx", "image/png"},
+		{"pdf", pdfHead, "plan.pdf", "application/pdf"},
+		{"pdf named as image", pdfHead, "plan.jpg", "application/pdf"},
+		{"zip as docx", zipHead, "report.DOCX", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"zip as xlsx", zipHead, "budget.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{"zip as ods", zipHead, "budget.ods", "application/vnd.oasis.opendocument.spreadsheet"},
+		{"zip named as csv", zipHead, "data.csv", "application/zip"},
+		{"plain zip", zipHead, "archive.zip", "application/zip"},
+		{"text as csv", textHead, "data.csv", "text/csv"},
+		{"text named as docx", textHead, "data.docx", "text/plain"},
+		{"plain text", textHead, "notes.txt", "text/plain"},
+		{"html named as csv", htmlHead, "data.csv", "text/html"},
+		{"binary", []byte{0x00, 0x01, 0x02, 0xff}, "blob.bin", "application/octet-stream"},
+		{"empty", nil, "empty.csv", "text/csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectContentType(tt.head, tt.filename); got != tt.want {
+				t.Errorf("detectContentType(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeAllowed(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf", "text/*x"}
+
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"image/png", true},
+		{"image/svg+xml", true},
+		{"application/pdf", true},
+		{"application/zip", false},
+		{"imagex/png", false},
+		{"text/plain", false},
+	}
+
+	for _, tt := range tests {
+		if got := typeAllowed(allowed, tt.contentType); got != tt.want {
+			t.Errorf("typeAllowed(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestCleanFilename(t *testing.T) {
+	long := make([]rune, maxFilenameLength+10)
+	for i := range long {
+		long[i] = 'ä'
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"  report.pdf  ", "report.pdf"},
+		{"dir/sub/report.pdf", "report.pdf"},
+		{`C:\Users\me\report.pdf`, "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{"re\x00po\r\nrt.pdf", "report.pdf"},
+		{"résumé.pdf", "résumé.pdf"},
+		{"", "file"},
+		{".", "file"},
+		{"..", "file"},
+		{"/", "file"},
+		{"dir/", "dir"},
+		{"\x07\x08", "file"},
+		{string(long), string(long[:maxFilenameLength])},
+	}
+
+	for _, tt := range tests {
+		if got := cleanFilename(tt.name); got != tt.want {
+			t.Errorf("cleanFilename(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
@@ -0,0 +1,303 @@
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead is allowed on top of the file size for the multipart
+// boundaries and part headers of an upload
+const multipartOverhead = 1 << 20
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List project attachments
+// @Description Page through the files attached to a project, newest first, each with a short-lived signed download link
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param limit query int false "Number of attachments to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of attachments"
+// @Success 200 {object} pagination.Page[models.Attachment]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/attachments [get]
+func (h *Handler) ListAttachments(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachments, err := h.service.ListAttachments(c.Request.Context(), user, projectID, params)
+	if err != nil {
+		h.respondError(c, "Failed to list attachments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// @Summary Upload an attachment
+// @Description Upload a file to a project as multipart form data. The size and content type are checked against the configured limits.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} models.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id}/attachments [post]
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.respondError(c, "Failed to upload attachment", ErrFileTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" form field"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error("Failed to open uploaded file", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload attachment"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.UploadAttachment(c.Request.Context(), user, projectID, &Upload{
+		Filename: header.Filename,
+		Size:     header.Size,
+		Body:     file,
+	})
+	if err != nil {
+		h.respondError(c, "Failed to upload attachment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// @Summary Get an attachment
+// @Description Get attachment metadata with a fresh short-lived signed download link
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} models.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/attachments/{attachmentId} [get]
+func (h *Handler) GetAttachment(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, id, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(c.Request.Context(), user, projectID, id)
+	if err != nil {
+		h.respondError(c, "Failed to get attachment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+// @Summary Download an attachment
+// @Description Download the contents of an attachment
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/attachments/{attachmentId}/download [get]
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, id, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, body, err := h.service.OpenAttachment(c.Request.Context(), user, projectID, id)
+	if err != nil {
+		h.respondError(c, "Failed to download attachment", err)
+		return
+	}
+	defer body.Close()
+
+	sendFile(c, attachment, body)
+}
+
+// @Summary Download an attachment by signed link
+// @Description Download an attachment with the download_url returned by the attachment endpoints. No authentication is needed until the link expires.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param expires query int true "Link expiry as a Unix timestamp"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /attachments/{id}/content [get]
+func (h *Handler) DownloadSigned(c *gin.Context) {
+	id, ok := api.ParamID(c, "id", "Invalid attachment ID")
+	if !ok {
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		h.respondError(c, "Failed to download attachment", ErrInvalidLink)
+		return
+	}
+
+	attachment, body, err := h.service.OpenSigned(c.Request.Context(), id, expires, c.Query("signature"))
+	if err != nil {
+		h.respondError(c, "Failed to download attachment", err)
+		return
+	}
+	defer body.Close()
+
+	sendFile(c, attachment, body)
+}
+
+// @Summary Delete an attachment
+// @Description Delete an attachment and its stored file
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/attachments/{attachmentId} [delete]
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, id, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAttachment(c.Request.Context(), user, projectID, id); err != nil {
+		h.respondError(c, "Failed to delete attachment", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// sendFile streams the attachment as a download. nosniff keeps browsers
+// from rendering it as anything other than its checked content type.
+func sendFile(c *gin.Context, attachment *models.Attachment, body io.Reader) {
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+		"ETag":                   `"` + attachment.Checksum + `"`,
+	})
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrAttachmentNotFound, Status: http.StatusNotFound, Message: "Attachment not found"},
+		api.ErrorResponse{Err: projects.ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: ErrFileTooLarge, Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("File is larger than the %d byte limit", h.service.MaxSize())},
+		api.ErrorResponse{Err: ErrTypeNotAllowed, Status: http.StatusUnsupportedMediaType},
+		api.ErrorResponse{Err: ErrEmptyFile, Status: http.StatusUnprocessableEntity},
+		api.ErrorResponse{Err: ErrInvalidLink, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: projects.ErrNoWriteAccess, Status: http.StatusForbidden},
+	)
+}
+
+func parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, ok := api.ParamID(c, "attachmentId", "Invalid attachment ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return projectID, id, true
+}
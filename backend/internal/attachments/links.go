@@ -0,0 +1,53 @@
+package attachments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// signedLinkPath is where the API serves signed download links, for blob
+// stores that cannot sign links themselves
+const signedLinkPath = "/api/attachments"
+
+// addLink sets a download link valid for the configured TTL. Stores that
+// can presign links are downloaded from directly; otherwise the link points
+// at the API and carries an HMAC over the attachment ID and expiry.
+func (s *Service) addLink(attachment *models.Attachment) error {
+	expires := time.Now().Add(s.config.LinkTTL).UTC().Truncate(time.Second)
+
+	if presigner, ok := s.store.(storage.Presigner); ok {
+		url, err := presigner.PresignGet(attachment.StorageKey, attachment.Filename, attachment.ContentType, s.config.LinkTTL)
+		if err != nil {
+			return fmt.Errorf("failed to sign download link: %w", err)
+		}
+		attachment.DownloadURL = url
+	} else {
+		attachment.DownloadURL = fmt.Sprintf("%s/%s/content?expires=%d&signature=%s",
+			signedLinkPath, attachment.ID, expires.Unix(), s.linkSignature(attachment.ID, expires.Unix()))
+	}
+
+	attachment.DownloadExpiresAt = &expires
+	return nil
+}
+
+func (s *Service) linkSignature(id uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	mac.Write([]byte(id.String() + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) verifyLink(id uuid.UUID, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.linkSignature(id, expires)))
+}
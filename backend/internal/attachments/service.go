@@ -0,0 +1,354 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"project-management-backend/internal/config"
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+	"project-management-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrEmptyFile          = errors.New("file is empty")
+	ErrFileTooLarge       = errors.New("file is too large")
+	ErrTypeNotAllowed     = errors.New("file type is not allowed")
+	ErrInvalidLink        = errors.New("download link is invalid or has expired")
+)
+
+// cleanupBatch is how many queued blob deletions are handled per query
+const cleanupBatch = 100
+
+type Service struct {
+	db       *db.Database
+	projects *projects.Service
+	store    storage.BlobStore
+	config   config.AttachmentsConfig
+	logger   *zap.Logger
+}
+
+func NewService(database *db.Database, projectsSvc *projects.Service, store storage.BlobStore, cfg config.AttachmentsConfig, logger *zap.Logger) *Service {
+	return &Service{
+		db:       database,
+		projects: projectsSvc,
+		store:    store,
+		config:   cfg,
+		logger:   logger,
+	}
+}
+
+// Upload is a file received for a project. Size is the length the client
+// declared; the stored file must match it.
+type Upload struct {
+	Filename string
+	Size     int64
+	Body     io.Reader
+}
+
+// MaxSize is the largest file accepted, in bytes
+func (s *Service) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+const attachmentColumns = `id, project_id, filename, content_type, size_bytes, checksum_sha256, storage_key, uploaded_by, created_at`
+
+func scanAttachment(row pgx.Row) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(&attachment.ID, &attachment.ProjectID, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.Checksum, &attachment.StorageKey, &attachment.UploadedBy, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListAttachments returns one page of the attachments of a project, newest
+// first, each with a fresh download link
+func (s *Service) ListAttachments(ctx context.Context, user *models.User, projectID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.Attachment], error) {
+	if _, err := s.projects.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	cursor, args := params.KeysetCondition([]interface{}{projectID})
+	args = append(args, params.Fetch())
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM project_attachments
+		WHERE project_id = $1 AND `+cursor+`
+		ORDER BY `+pagination.KeysetOrder+`
+		LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	page := pagination.NewPage(attachments, params, func(last *models.Attachment) pagination.Cursor {
+		return pagination.After(last.CreatedAt, last.ID)
+	})
+
+	for _, attachment := range page.Items {
+		if err := s.addLink(attachment); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM project_attachments WHERE project_id = $1", projectID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count attachments: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// GetAttachment returns the attachment with a fresh download link
+func (s *Service) GetAttachment(ctx context.Context, user *models.User, projectID, id uuid.UUID) (*models.Attachment, error) {
+	attachment, err := s.getAttachment(ctx, user, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addLink(attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *Service) getAttachment(ctx context.Context, user *models.User, projectID, id uuid.UUID) (*models.Attachment, error) {
+	if _, err := s.projects.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := scanAttachment(s.db.Pool.QueryRow(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM project_attachments
+		WHERE id = $1 AND project_id = $2`, id, projectID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// UploadAttachment checks the file against the size and type limits,
+// stores it and records its metadata. The content type is detected from
+// the file itself rather than trusted from the client.
+func (s *Service) UploadAttachment(ctx context.Context, user *models.User, projectID uuid.UUID, upload *Upload) (*models.Attachment, error) {
+	project, err := s.projects.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+
+	if upload.Size <= 0 {
+		return nil, ErrEmptyFile
+	}
+	if upload.Size > s.config.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	contentType := detectContentType(head, upload.Filename)
+	if !typeAllowed(s.config.AllowedTypes, contentType) {
+		return nil, ErrTypeNotAllowed
+	}
+
+	id := uuid.New()
+	key := fmt.Sprintf("projects/%s/%s", projectID, id)
+	checksum := sha256.New()
+	body := io.TeeReader(io.MultiReader(bytes.NewReader(head), upload.Body), checksum)
+	if err := s.store.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment, err := scanAttachment(s.db.Pool.QueryRow(ctx, `
+		INSERT INTO project_attachments (id, project_id, filename, content_type, size_bytes, checksum_sha256, storage_key, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+attachmentColumns,
+		id, projectID, cleanFilename(upload.Filename), contentType, upload.Size,
+		hex.EncodeToString(checksum.Sum(nil)), key, user.ID))
+	if err != nil {
+		if deleteErr := s.store.Delete(ctx, key); deleteErr != nil {
+			s.logger.Error("Failed to remove blob of unsaved attachment",
+				zap.String("storage_key", key), zap.Error(deleteErr))
+		}
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	s.logger.Info("Attachment uploaded",
+		zap.String("attachment_id", attachment.ID.String()),
+		zap.String("project_id", projectID.String()),
+		zap.Int64("size", attachment.Size),
+		zap.String("uploaded_by", user.ID.String()))
+
+	if err := s.addLink(attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns the attachment and its contents, which the caller
+// must close
+func (s *Service) OpenAttachment(ctx context.Context, user *models.User, projectID, id uuid.UUID) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.getAttachment(ctx, user, projectID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.open(ctx, attachment)
+}
+
+// OpenSigned returns the attachment named by a signed download link
+func (s *Service) OpenSigned(ctx context.Context, id uuid.UUID, expires int64, signature string) (*models.Attachment, io.ReadCloser, error) {
+	if !s.verifyLink(id, expires, signature) {
+		return nil, nil, ErrInvalidLink
+	}
+
+	attachment, err := scanAttachment(s.db.Pool.QueryRow(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM project_attachments
+		WHERE id = $1
+		  AND EXISTS (SELECT 1 FROM projects p WHERE p.id = project_id AND p.deleted_at IS NULL)`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return s.open(ctx, attachment)
+}
+
+func (s *Service) open(ctx context.Context, attachment *models.Attachment) (*models.Attachment, io.ReadCloser, error) {
+	body, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment %s: %w", attachment.ID, err)
+	}
+	return attachment, body, nil
+}
+
+// DeleteAttachment removes the attachment. Its blob is queued for deletion
+// and removed from the store by RunBlobCleanup.
+func (s *Service) DeleteAttachment(ctx context.Context, user *models.User, projectID, id uuid.UUID) error {
+	project, err := s.projects.GetProject(ctx, user, projectID)
+	if err != nil {
+		return err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return err
+	}
+
+	result, err := s.db.Pool.Exec(ctx,
+		"DELETE FROM project_attachments WHERE id = $1 AND project_id = $2", id, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	s.logger.Info("Attachment deleted",
+		zap.String("attachment_id", id.String()),
+		zap.String("project_id", projectID.String()),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
+// CleanupBlobs deletes the blobs queued by removed attachments from the
+// store and returns how many were deleted
+func (s *Service) CleanupBlobs(ctx context.Context) (int, error) {
+	deleted := 0
+	for {
+		rows, err := s.db.Pool.Query(ctx,
+			"SELECT storage_key FROM attachment_blob_deletions ORDER BY queued_at LIMIT $1", cleanupBatch)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list queued blob deletions: %w", err)
+		}
+		var keys []string
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return deleted, fmt.Errorf("failed to scan storage key: %w", err)
+			}
+			keys = append(keys, key)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return deleted, fmt.Errorf("failed to list queued blob deletions: %w", err)
+		}
+
+		for _, key := range keys {
+			if err := s.store.Delete(ctx, key); err != nil {
+				return deleted, fmt.Errorf("failed to delete blob %s: %w", key, err)
+			}
+			if _, err := s.db.Pool.Exec(ctx, "DELETE FROM attachment_blob_deletions WHERE storage_key = $1", key); err != nil {
+				return deleted, fmt.Errorf("failed to dequeue blob deletion: %w", err)
+			}
+			deleted++
+		}
+
+		if len(keys) < cleanupBatch {
+			return deleted, nil
+		}
+	}
+}
+
+// RunBlobCleanup calls CleanupBlobs every interval until ctx is cancelled
+func (s *Service) RunBlobCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.CleanupBlobs(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to clean up attachment blobs", zap.Error(err))
+		} else if deleted > 0 {
+			s.logger.Info("Deleted blobs of removed attachments", zap.Int("count", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
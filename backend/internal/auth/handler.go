@@ -1,9 +1,13 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 
+	"project-management-backend/internal/api"
 	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -34,9 +38,7 @@ func NewHandler(service *Service, logger *zap.Logger) *Handler {
 // @Router /auth/signup [post]
 func (h *Handler) Signup(c *gin.Context) {
 	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Invalid signup request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
@@ -47,20 +49,13 @@ func (h *Handler) Signup(c *gin.Context) {
 		return
 	}
 
-	// Generate session token
-	token, expiresAt, err := h.service.GenerateSessionToken(user)
+	response, err := h.newAuthResponse(c, user)
 	if err != nil {
-		h.logger.Error("Failed to generate session token", zap.Error(err))
+		h.logger.Error("Failed to create session", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
 
-	response := &models.AuthResponse{
-		User:         user,
-		SessionToken: token,
-		ExpiresAt:    expiresAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -76,21 +71,68 @@ func (h *Handler) Signup(c *gin.Context) {
 // @Router /auth/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Invalid login request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
-	user, err := h.service.AuthenticateUser(c.Request.Context(), &req)
+	user, err := h.service.AuthenticateUser(c.Request.Context(), &req, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		h.logger.Warn("Authentication failed", zap.String("username", req.Username), zap.Error(err))
+
+		var suspended *AccountSuspendedError
+		if errors.As(err, &suspended) {
+			c.JSON(http.StatusLocked, gin.H{
+				"error":             "Account is suspended",
+				"suspended_until":   suspended.Until,
+				"suspension_reason": suspended.Reason,
+			})
+			return
+		}
+
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Generate session token
-	token, expiresAt, err := h.service.GenerateSessionToken(user)
+	response, err := h.newAuthResponse(c, user)
+	if err != nil {
+		h.logger.Error("Failed to create session", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Refresh session
+// @Description Exchange a refresh token for a new session and refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/refresh [post]
+func (h *Handler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	rotated, err := h.service.RotateRefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.logger.Warn("Refresh failed", zap.Error(err))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	if err := h.service.ApplyEffectiveRole(c.Request.Context(), rotated.User); err != nil {
+		h.logger.Error("Failed to resolve effective role", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	token, expiresAt, err := h.service.GenerateSessionToken(rotated.User, rotated.SessionID)
 	if err != nil {
 		h.logger.Error("Failed to generate session token", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
@@ -98,14 +140,64 @@ func (h *Handler) Login(c *gin.Context) {
 	}
 
 	response := &models.AuthResponse{
-		User:         user,
-		SessionToken: token,
-		ExpiresAt:    expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		User:                  rotated.User,
+		SessionToken:          token,
+		ExpiresAt:             expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		RefreshToken:          rotated.RefreshToken,
+		RefreshTokenExpiresAt: rotated.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary Unlock a suspended account
+// @Description Lift a login suspension and reset the failed login counter (admin only, for accounts with a lower role)
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/unlock/{userId} [post]
+func (h *Handler) UnlockUser(c *gin.Context) {
+	actorModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := api.ParamID(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	user, err := h.service.UnlockUser(c.Request.Context(), actorModel, userID)
+	if err != nil {
+		h.logger.Warn("Failed to unlock user", zap.String("user_id", userID.String()), zap.Error(err))
+		if errors.Is(err, ErrCannotManageUser) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Info("Account unlocked",
+		zap.String("user_id", user.ID.String()),
+		zap.String("unlocked_by", actorModel.ID.String()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account unlocked successfully",
+		"user":    user,
+	})
+}
+
 // @Summary Get current user
 // @Description Get the current authenticated user's information
 // @Tags auth
@@ -115,15 +207,8 @@ func (h *Handler) Login(c *gin.Context) {
 // @Failure 401 {object} map[string]string
 // @Router /auth/me [get]
 func (h *Handler) GetCurrentUser(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	userModel, ok := user.(*models.User)
+	userModel, ok := api.CurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
 		return
 	}
 
@@ -142,22 +227,19 @@ func (h *Handler) GetCurrentUser(c *gin.Context) {
 // @Failure 401 {object} map[string]string
 // @Router /auth/tokens [post]
 func (h *Handler) CreateToken(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
 		return
 	}
 
-	userModel, ok := user.(*models.User)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
+	// A token minted while impersonating would outlive the impersonation
+	if userModel.ImpersonatedBy != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API tokens cannot be created while impersonating"})
 		return
 	}
 
 	var req models.CreateTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Invalid token creation request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
@@ -185,36 +267,43 @@ func (h *Handler) CreateToken(c *gin.Context) {
 }
 
 // @Summary Get user's API tokens
-// @Description Get all API tokens for the authenticated user
+// @Description Page through the API tokens of the authenticated user, newest first
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.TokenResponse
+// @Param limit query int false "Number of tokens to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of tokens"
+// @Success 200 {object} pagination.Page[models.TokenResponse]
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /auth/tokens [get]
 func (h *Handler) GetTokens(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
 		return
 	}
 
-	userModel, ok := user.(*models.User)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	tokens, err := h.service.GetUserTokens(c.Request.Context(), userModel.ID)
+	tokens, err := h.service.GetUserTokens(c.Request.Context(), userModel.ID, params)
 	if err != nil {
 		h.logger.Error("Failed to get user tokens", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tokens"})
 		return
 	}
 
-	var response []models.TokenResponse
-	for _, token := range tokens {
-		response = append(response, models.TokenResponse{
+	response := &pagination.Page[models.TokenResponse]{
+		Items:      make([]models.TokenResponse, 0, len(tokens.Items)),
+		NextCursor: tokens.NextCursor,
+		Total:      tokens.Total,
+	}
+	for _, token := range tokens.Items {
+		response.Items = append(response.Items, models.TokenResponse{
 			ID:         token.ID,
 			Name:       token.Name,
 			Token:      token.Token,
@@ -239,26 +328,17 @@ func (h *Handler) GetTokens(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /auth/tokens/{id} [delete]
 func (h *Handler) RevokeToken(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	userModel, ok := user.(*models.User)
+	userModel, ok := api.CurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
 		return
 	}
 
-	tokenIDStr := c.Param("id")
-	tokenID, err := uuid.Parse(tokenIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+	tokenID, ok := api.ParamID(c, "id", "Invalid token ID")
+	if !ok {
 		return
 	}
 
-	err = h.service.RevokeToken(c.Request.Context(), tokenID, userModel.ID)
+	err := h.service.RevokeToken(c.Request.Context(), tokenID, userModel.ID)
 	if err != nil {
 		h.logger.Error("Failed to revoke token", zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -269,15 +349,446 @@ func (h *Handler) RevokeToken(c *gin.Context) {
 }
 
 // @Summary Logout
-// @Description Logout the current user (client should discard token)
+// @Description Logout the current user by revoking the current session
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Router /auth/logout [post]
 func (h *Handler) Logout(c *gin.Context) {
-	// In a stateless JWT system, logout is handled client-side
-	// The client should discard the token
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	// API tokens carry no session; they are revoked through /auth/tokens
+	sessionIDStr := c.GetString("session_id")
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No session to log out"})
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), sessionID, userModel.ID); err != nil {
+		h.logger.Error("Failed to revoke session", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
+// @Summary List active sessions
+// @Description Get all active sessions for the authenticated user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SessionResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/sessions [get]
+func (h *Handler) GetSessions(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.service.GetUserSessions(c.Request.Context(), userModel.ID)
+	if err != nil {
+		h.logger.Error("Failed to get user sessions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sessions"})
+		return
+	}
+
+	currentSessionID := c.GetString("session_id")
+	response := []models.SessionResponse{}
+	for _, session := range sessions {
+		response = append(response, models.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID.String() == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke a session
+// @Description Revoke one of the authenticated user's sessions
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	sessionID, ok := api.ParamID(c, "id", "Invalid session ID")
+	if !ok {
+		return
+	}
+
+	err := h.service.RevokeSession(c.Request.Context(), sessionID, userModel.ID)
+	if err != nil {
+		h.logger.Error("Failed to revoke session", zap.Error(err))
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Revoke all sessions
+// @Description Revoke every session of the authenticated user, including the current one
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Router /auth/sessions [delete]
+func (h *Handler) RevokeAllSessions(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.service.RevokeAllSessions(c.Request.Context(), userModel.ID)
+	if err != nil {
+		h.logger.Error("Failed to revoke sessions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked", "revoked": count})
+}
+
+// newAuthResponse records a new session for a freshly authenticated user and
+// issues its session token and first refresh token.
+func (h *Handler) newAuthResponse(c *gin.Context, user *models.User) (*models.AuthResponse, error) {
+	if err := h.service.ApplyEffectiveRole(c.Request.Context(), user); err != nil {
+		return nil, err
+	}
+
+	session, err := h.service.CreateSession(c.Request.Context(), user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := h.service.GenerateSessionToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExpiresAt, err := h.service.IssueRefreshToken(c.Request.Context(), user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		User:                  user,
+		SessionToken:          token,
+		ExpiresAt:             expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// @Summary Switch to a lower role
+// @Description Temporarily act with a lower role than your own, optionally until it expires
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SwitchRoleRequest true "Target role"
+// @Success 200 {object} models.RoleSwitchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/switch-role [post]
+func (h *Handler) SwitchRole(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if userModel.ImpersonatedBy != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Role switching is not allowed while impersonating"})
+		return
+	}
+
+	var req models.SwitchRoleRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	inheritance, err := h.service.SwitchRole(c.Request.Context(), userModel.ID, &req)
+	if err != nil {
+		h.logger.Warn("Failed to switch role", zap.String("user_id", userModel.ID.String()), zap.Error(err))
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	switched := *userModel
+	switched.Role = inheritance.InheritedRole
+	switched.OriginalRole = &inheritance.OriginalRole
+	switched.RoleExpiresAt = inheritance.ExpiresAt
+
+	response := &models.RoleSwitchResponse{
+		Message:     "Switched to " + string(inheritance.InheritedRole) + " role",
+		Inheritance: inheritance,
+	}
+	if err := h.reissueSessionToken(c, &switched, response); err != nil {
+		h.logger.Error("Failed to generate session token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Return to original role
+// @Description End the active role switch and act with your own role again
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RoleSwitchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/return-role [post]
+func (h *Handler) ReturnRole(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if userModel.ImpersonatedBy != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Role switching is not allowed while impersonating"})
+		return
+	}
+
+	inheritance, err := h.service.ReturnRole(c.Request.Context(), userModel.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	restored := *userModel
+	restored.Role = inheritance.OriginalRole
+	restored.OriginalRole = nil
+	restored.RoleExpiresAt = nil
+
+	response := &models.RoleSwitchResponse{
+		Message: "Returned to " + string(inheritance.OriginalRole) + " role",
+	}
+	if err := h.reissueSessionToken(c, &restored, response); err != nil {
+		h.logger.Error("Failed to generate session token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get role status
+// @Description Get the original and effective role of the authenticated user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RoleStatusResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/role-status [get]
+func (h *Handler) GetRoleStatus(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.service.GetRoleStatus(c.Request.Context(), userModel.ID)
+	if err != nil {
+		h.logger.Error("Failed to get role status", zap.Error(err))
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// reissueSessionToken issues a new session token for the current session so
+// that a role change takes effect immediately. API token callers have no
+// session; their effective role is resolved on every request instead.
+func (h *Handler) reissueSessionToken(c *gin.Context, user *models.User, response *models.RoleSwitchResponse) error {
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		return nil
+	}
+
+	token, expiresAt, err := h.service.GenerateSessionToken(user, sessionID)
+	if err != nil {
+		return err
+	}
+
+	response.SessionToken = token
+	response.ExpiresAt = expiresAt.Format("2006-01-02T15:04:05Z07:00")
+	return nil
+}
+
+// @Summary Impersonate a user
+// @Description Act as another user with a lower role (sysadmin and superuser only)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ImpersonateRequest true "Target user"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/impersonate [post]
+func (h *Handler) Impersonate(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if userModel.ImpersonatedBy != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Already impersonating a user"})
+		return
+	}
+
+	// Impersonation returns to the actor's own session when it ends
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Impersonation requires a session token"})
+		return
+	}
+
+	var req models.ImpersonateRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	actor := &models.Actor{
+		ID:        userModel.ID,
+		Username:  userModel.Username,
+		Role:      userModel.Role,
+		SessionID: sessionID,
+	}
+
+	target, session, err := h.service.StartImpersonation(c.Request.Context(), actor, &req, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		h.logger.Warn("Impersonation rejected",
+			zap.String("actor_id", actor.ID.String()),
+			zap.Error(err))
+		switch err.Error() {
+		case "target user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "target_user_id or target_username is required", "cannot impersonate yourself":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	token, expiresAt, err := h.service.GenerateSessionToken(target, session.ID)
+	if err != nil {
+		h.logger.Error("Failed to generate session token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.AuthResponse{
+		User:         target,
+		SessionToken: token,
+		ExpiresAt:    expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// @Summary Stop impersonation
+// @Description End the impersonation session and return to the administrator's own session
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/stop-impersonation [post]
+func (h *Handler) StopImpersonation(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if userModel.ImpersonatedBy == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not currently impersonating"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not currently impersonating"})
+		return
+	}
+
+	actor := userModel.ImpersonatedBy
+	actorUser, err := h.service.StopImpersonation(c.Request.Context(), userModel.ID, sessionID, actor)
+	if errors.Is(err, ErrActorSessionEnded) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Your own session has expired or was revoked; log in again"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to stop impersonation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop impersonation"})
+		return
+	}
+
+	token, expiresAt, err := h.service.GenerateSessionToken(actorUser, actor.SessionID)
+	if err != nil {
+		h.logger.Error("Failed to generate session token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.AuthResponse{
+		User:         actorUser,
+		SessionToken: token,
+		ExpiresAt:    expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// @Summary Get impersonation status
+// @Description Report whether the current token is an impersonation token and who the actor is
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ImpersonationStatusResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/impersonation-status [get]
+func (h *Handler) GetImpersonationStatus(c *gin.Context) {
+	userModel, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.ImpersonationStatusResponse{
+		Impersonating: userModel.ImpersonatedBy != nil,
+		User:          userModel,
+		Actor:         userModel.ImpersonatedBy,
+	})
+}
+
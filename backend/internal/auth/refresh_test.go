@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeTx answers QueryRow from rows keyed by the table the query reads and
+// records every Exec. Methods the tests do not need are left to the
+// embedded nil interface.
+type fakeTx struct {
+	pgx.Tx
+	rows map[string][]interface{}
+	// sessionRows is what the session UPDATE reports as affected
+	sessionRows int
+	execs       []execCall
+}
+
+type execCall struct {
+	sql  string
+	args []interface{}
+}
+
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, d := range dest {
+		target := reflect.ValueOf(d).Elem()
+		if r.values[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		target.Set(reflect.ValueOf(r.values[i]))
+	}
+	return nil
+}
+
+func (tx *fakeTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	for table, values := range tx.rows {
+		if strings.Contains(sql, "FROM "+table) {
+			return fakeRow{values: values}
+		}
+	}
+	return fakeRow{err: pgx.ErrNoRows}
+}
+
+func (tx *fakeTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tx.execs = append(tx.execs, execCall{sql: strings.Join(strings.Fields(sql), " "), args: args})
+	if strings.Contains(sql, "UPDATE user_sessions") && strings.Contains(sql, "expires_at") && tx.sessionRows == 0 {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+type storedToken struct {
+	id, userID, familyID uuid.UUID
+	expiresAt            time.Time
+	rotatedAt, revokedAt *time.Time
+}
+
+func newRefreshTx(token storedToken, userID uuid.UUID) *fakeTx {
+	return &fakeTx{
+		rows: map[string][]interface{}{
+			"refresh_tokens": {token.id, token.userID, token.familyID, token.expiresAt, token.rotatedAt, token.revokedAt},
+			"users":          {userID, "alice", "alice@example.com", models.RoleUser, time.Time{}, time.Time{}},
+		},
+		sessionRows: 1,
+	}
+}
+
+func TestRotateRefreshTokenReuseRevokesFamily(t *testing.T) {
+	s := newTestService(0, 0)
+	now := time.Now()
+	rotated := now.Add(-time.Minute)
+	token := storedToken{id: uuid.New(), userID: uuid.New(), familyID: uuid.New(), expiresAt: now.Add(time.Hour), rotatedAt: &rotated}
+	tx := newRefreshTx(token, token.userID)
+
+	result, err := s.rotateRefreshToken(context.Background(), tx, "old-token", now)
+
+	if !errors.Is(err, errRefreshTokenReused) || result != nil {
+		t.Fatalf("rotateRefreshToken() = %v, %v; want errRefreshTokenReused", result, err)
+	}
+	if len(tx.execs) != 2 {
+		t.Fatalf("ran %d statements, want 2: %+v", len(tx.execs), tx.execs)
+	}
+
+	family, session := tx.execs[0], tx.execs[1]
+	if !strings.HasPrefix(family.sql, "UPDATE refresh_tokens SET revoked_at") || !strings.Contains(family.sql, "WHERE family_id = $2") || family.args[1] != token.familyID {
+		t.Errorf("family revocation = %+v", family)
+	}
+	if !strings.HasPrefix(session.sql, "UPDATE user_sessions SET revoked_at") || session.args[1] != token.familyID {
+		t.Errorf("session revocation = %+v", session)
+	}
+}
+
+func TestRotateRefreshTokenRejects(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	base := storedToken{id: uuid.New(), userID: uuid.New(), familyID: uuid.New(), expiresAt: now.Add(time.Hour)}
+
+	revoked := base
+	revoked.revokedAt = &past
+	expired := base
+	expired.expiresAt = past
+
+	tests := []struct {
+		name  string
+		tx    *fakeTx
+		error string
+	}{
+		{"unknown token", &fakeTx{}, "invalid refresh token"},
+		{"revoked token", newRefreshTx(revoked, base.userID), "refresh token revoked"},
+		{"expired token", newRefreshTx(expired, base.userID), "refresh token expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(0, 0)
+			_, err := s.rotateRefreshToken(context.Background(), tt.tx, "token", now)
+			if err == nil || err.Error() != tt.error {
+				t.Errorf("error = %v, want %q", err, tt.error)
+			}
+			if len(tt.tx.execs) != 0 {
+				t.Errorf("ran statements %+v", tt.tx.execs)
+			}
+		})
+	}
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	s := newTestService(0, 0)
+	now := time.Now()
+	token := storedToken{id: uuid.New(), userID: uuid.New(), familyID: uuid.New(), expiresAt: now.Add(time.Hour)}
+	tx := newRefreshTx(token, token.userID)
+
+	result, err := s.rotateRefreshToken(context.Background(), tx, "old-token", now)
+	if err != nil {
+		t.Fatalf("rotateRefreshToken() error = %v", err)
+	}
+
+	if result.SessionID != token.familyID || result.User.ID != token.userID {
+		t.Errorf("result = %+v", result)
+	}
+	if result.RefreshToken == "" || result.RefreshToken == "old-token" {
+		t.Errorf("RefreshToken = %q", result.RefreshToken)
+	}
+	if want := now.Add(s.config.Auth.RefreshDuration); !result.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", result.ExpiresAt, want)
+	}
+
+	if len(tx.execs) != 3 {
+		t.Fatalf("ran %d statements, want 3: %+v", len(tx.execs), tx.execs)
+	}
+	insert, rotate, extend := tx.execs[0], tx.execs[1], tx.execs[2]
+
+	// The new token joins the family, and only its hash is stored
+	if !strings.HasPrefix(insert.sql, "INSERT INTO refresh_tokens") || insert.args[2] != token.familyID || insert.args[3] != hashRefreshToken(result.RefreshToken) {
+		t.Errorf("insert = %+v", insert)
+	}
+	// The presented token is marked as replaced by the new one
+	if !strings.Contains(rotate.sql, "SET rotated_at = $1, replaced_by = $2") || rotate.args[1] != insert.args[0] || rotate.args[2] != token.id {
+		t.Errorf("rotation = %+v", rotate)
+	}
+	if !strings.Contains(extend.sql, "UPDATE user_sessions SET expires_at") || extend.args[2] != token.familyID {
+		t.Errorf("session extension = %+v", extend)
+	}
+}
+
+func TestRotateRefreshTokenRevokedSession(t *testing.T) {
+	s := newTestService(0, 0)
+	now := time.Now()
+	token := storedToken{id: uuid.New(), userID: uuid.New(), familyID: uuid.New(), expiresAt: now.Add(time.Hour)}
+	tx := newRefreshTx(token, token.userID)
+	tx.sessionRows = 0
+
+	if _, err := s.rotateRefreshToken(context.Background(), tx, "token", now); err == nil || err.Error() != "session revoked" {
+		t.Errorf("error = %v, want session revoked", err)
+	}
+}
+
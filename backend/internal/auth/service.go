@@ -3,20 +3,38 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"project-management-backend/internal/config"
 	"project-management-backend/internal/db"
 	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/argon2"
 )
 
+var (
+	// ErrCannotManageUser is returned when the actor's role does not outrank
+	// the target account's role
+	ErrCannotManageUser = errors.New("insufficient permissions for this user")
+	// ErrActorSessionEnded is returned when impersonation stops after the
+	// administrator's own session was revoked or expired
+	ErrActorSessionEnded = errors.New("actor session revoked or expired")
+
+	// errRefreshTokenReused is returned by rotateRefreshToken after it
+	// revoked the token family in its transaction, which the caller still
+	// has to commit
+	errRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
 type Service struct {
 	db     *db.Database
 	config *config.Config
@@ -24,9 +42,17 @@ type Service struct {
 }
 
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	OriginalRole string `json:"original_role,omitempty"`
+
+	// Actor claims are set on impersonation tokens and identify the
+	// administrator acting as UserID
+	ActorID        string `json:"actor_id,omitempty"`
+	ActorUsername  string `json:"actor_username,omitempty"`
+	ActorRole      string `json:"actor_role,omitempty"`
+	ActorSessionID string `json:"actor_session_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -79,37 +105,234 @@ func (s *Service) CreateUser(ctx context.Context, req *models.CreateUserRequest)
 	return user, nil
 }
 
-func (s *Service) AuthenticateUser(ctx context.Context, req *models.LoginRequest) (*models.User, error) {
+// AccountSuspendedError is returned by AuthenticateUser when the account is
+// locked after too many failed logins or by an administrator.
+type AccountSuspendedError struct {
+	Until  time.Time
+	Reason string
+}
+
+func (e *AccountSuspendedError) Error() string {
+	return fmt.Sprintf("account suspended until %s: %s", e.Until.Format(time.RFC3339), e.Reason)
+}
+
+// AuthenticateUser verifies the credentials and records the attempt. After
+// MaxFailedLogins consecutive failures the account is suspended for
+// LockoutDuration.
+func (s *Service) AuthenticateUser(ctx context.Context, req *models.LoginRequest, ipAddress, userAgent string) (*models.User, error) {
 	var user models.User
-	err := s.db.Pool.QueryRow(ctx,
-		"SELECT id, username, email, password, role, created_at, updated_at FROM users WHERE username = $1",
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT id, username, email, password, role, created_at, updated_at,
+		       suspended_until, suspension_reason, COALESCE(failed_login_count, 0)
+		FROM users WHERE username = $1 AND is_active`,
 		req.Username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.SuspendedUntil, &user.SuspensionReason, &user.FailedLoginCount)
 
 	if err != nil {
+		s.recordLoginAttempt(ctx, nil, req.Username, ipAddress, userAgent, false)
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	if user.IsSuspended() {
+		s.recordLoginAttempt(ctx, &user.ID, req.Username, ipAddress, userAgent, false)
+		return nil, &AccountSuspendedError{Until: *user.SuspendedUntil, Reason: suspensionReason(&user)}
+	}
+
 	// Verify password
 	if !s.verifyPassword(req.Password, user.Password) {
-		return nil, fmt.Errorf("invalid credentials")
+		s.recordLoginAttempt(ctx, &user.ID, req.Username, ipAddress, userAgent, false)
+		return nil, s.recordFailedLogin(ctx, user.ID)
+	}
+
+	s.recordLoginAttempt(ctx, &user.ID, req.Username, ipAddress, userAgent, true)
+
+	// Successful login clears the failure counter and any lapsed suspension
+	if user.FailedLoginCount > 0 || user.SuspendedUntil != nil {
+		_, err = s.db.Pool.Exec(ctx, `
+			UPDATE users
+			SET failed_login_count = 0, last_failed_login = NULL, suspended_until = NULL, suspension_reason = NULL
+			WHERE id = $1`,
+			user.ID)
+		if err != nil {
+			s.logger.Warn("Failed to reset failed login count", zap.Error(err))
+		}
+		user.FailedLoginCount = 0
+		user.SuspendedUntil = nil
+		user.SuspensionReason = nil
 	}
 
 	s.logger.Info("User authenticated", zap.String("user_id", user.ID.String()), zap.String("username", user.Username))
 	return &user, nil
 }
 
-func (s *Service) GenerateSessionToken(user *models.User) (string, time.Time, error) {
+// UnlockUser lifts a suspension and resets the failed login counter. The
+// actor must be allowed to manage the account's role.
+func (s *Service) UnlockUser(ctx context.Context, actor *models.User, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := s.db.Pool.QueryRow(ctx,
+		"SELECT id, username, email, role, created_at, updated_at, suspended_until FROM users WHERE id = $1",
+		userID).Scan(
+		&user.ID, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.SuspendedUntil)
+
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if !actor.CanManageRole(user.Role) {
+		return nil, ErrCannotManageUser
+	}
+
+	if !user.IsSuspended() {
+		return nil, fmt.Errorf("user is not currently suspended")
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE users
+		SET failed_login_count = 0, last_failed_login = NULL, suspended_until = NULL, suspension_reason = NULL
+		WHERE id = $1`,
+		userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	user.SuspendedUntil = nil
+	s.logger.Info("User unlocked", zap.String("user_id", user.ID.String()), zap.String("username", user.Username))
+	return &user, nil
+}
+
+// recordFailedLogin bumps the consecutive failure counter and suspends the
+// account once it reaches MaxFailedLogins. It returns the error to report to
+// the caller.
+func (s *Service) recordFailedLogin(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to record failed login", zap.Error(err))
+		return fmt.Errorf("invalid credentials")
+	}
+	defer tx.Rollback(ctx)
+
+	// The row lock makes concurrent failures count one after another, so
+	// parallel guesses cannot all write back the same counter
+	user := models.User{ID: userID}
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(failed_login_count, 0), suspended_until, suspension_reason
+		FROM users WHERE id = $1
+		FOR UPDATE`,
+		userID).Scan(&user.FailedLoginCount, &user.SuspendedUntil, &user.SuspensionReason)
+	if err != nil {
+		s.logger.Warn("Failed to record failed login", zap.Error(err))
+		return fmt.Errorf("invalid credentials")
+	}
+
+	suspended := s.applyFailedLogin(&user, now)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET failed_login_count = $1, last_failed_login = $2, suspended_until = $3, suspension_reason = $4
+		WHERE id = $5`,
+		user.FailedLoginCount, now, user.SuspendedUntil, user.SuspensionReason, userID)
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to record failed login", zap.Error(err))
+	}
+
+	if suspended {
+		s.logger.Warn("Account suspended after failed logins",
+			zap.String("user_id", userID.String()),
+			zap.Int("failed_login_count", user.FailedLoginCount))
+	}
+	if user.SuspendedUntil != nil && now.Before(*user.SuspendedUntil) {
+		return &AccountSuspendedError{Until: *user.SuspendedUntil, Reason: suspensionReason(&user)}
+	}
+
+	return fmt.Errorf("invalid credentials")
+}
+
+// applyFailedLogin counts one more failed login against the user's stored
+// counter and suspension, and reports whether this failure suspended the
+// account. A lapsed suspension starts a fresh window; an active one, set by
+// a concurrent attempt, is left as it is.
+func (s *Service) applyFailedLogin(user *models.User, now time.Time) bool {
+	if user.SuspendedUntil != nil {
+		if now.Before(*user.SuspendedUntil) {
+			user.FailedLoginCount++
+			return false
+		}
+		user.FailedLoginCount = 0
+		user.SuspendedUntil = nil
+		user.SuspensionReason = nil
+	}
+
+	user.FailedLoginCount++
+	if s.config.Auth.MaxFailedLogins <= 0 || user.FailedLoginCount < s.config.Auth.MaxFailedLogins {
+		return false
+	}
+
+	until := now.Add(s.config.Auth.LockoutDuration)
+	reason := "Too many failed login attempts"
+	user.SuspendedUntil = &until
+	user.SuspensionReason = &reason
+	return true
+}
+
+func (s *Service) recordLoginAttempt(ctx context.Context, userID *uuid.UUID, username, ipAddress, userAgent string, success bool) {
+	_, err := s.db.Pool.Exec(ctx, `
+		INSERT INTO login_attempts (user_id, username, ip_address, user_agent, success, attempted_at)
+		VALUES ($1, $2, NULLIF($3, '')::inet, $4, $5, $6)`,
+		userID, username, ipAddress, userAgent, success, time.Now())
+
+	if err != nil {
+		s.logger.Warn("Failed to record login attempt", zap.String("username", username), zap.Error(err))
+	}
+}
+
+func suspensionReason(user *models.User) string {
+	if user.SuspensionReason != nil && *user.SuspensionReason != "" {
+		return *user.SuspensionReason
+	}
+	return "Too many failed login attempts"
+}
+
+// GenerateSessionToken issues a session JWT for the user. The session ID is
+// carried as the jti claim so that RequireAuth can reject revoked sessions.
+// While a role switch is active the token carries the inherited role and
+// does not outlive the switch. Impersonation tokens also carry the actor.
+func (s *Service) GenerateSessionToken(user *models.User, sessionID uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.config.Auth.SessionDuration)
+	if user.ImpersonatedBy != nil {
+		expiresAt = now.Add(s.config.Auth.ImpersonationDuration)
+	}
+	if user.RoleExpiresAt != nil && user.RoleExpiresAt.Before(expiresAt) {
+		expiresAt = *user.RoleExpiresAt
+	}
+
 	claims := &Claims{
 		UserID:   user.ID.String(),
 		Username: user.Username,
 		Role:     string(user.Role),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.Auth.SessionDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        sessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+	if user.OriginalRole != nil {
+		claims.OriginalRole = string(*user.OriginalRole)
+	}
+	if actor := user.ImpersonatedBy; actor != nil {
+		claims.ActorID = actor.ID.String()
+		claims.ActorUsername = actor.Username
+		claims.ActorRole = string(actor.Role)
+		claims.ActorSessionID = actor.SessionID.String()
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(s.config.Auth.JWTSecret))
@@ -117,10 +340,605 @@ func (s *Service) GenerateSessionToken(user *models.User) (string, time.Time, er
 		return "", time.Time{}, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	expiresAt := time.Now().Add(s.config.Auth.SessionDuration)
 	return tokenString, expiresAt, nil
 }
 
+// ApplyEffectiveRole replaces the user's role with the inherited role when a
+// role switch is active. Expired switches are deactivated on the way.
+func (s *Service) ApplyEffectiveRole(ctx context.Context, user *models.User) error {
+	inheritance, err := s.getActiveInheritance(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if inheritance == nil {
+		user.OriginalRole = nil
+		user.RoleExpiresAt = nil
+		return nil
+	}
+
+	originalRole := user.Role
+	user.OriginalRole = &originalRole
+	user.Role = inheritance.InheritedRole
+	user.RoleExpiresAt = inheritance.ExpiresAt
+	return nil
+}
+
+// SwitchRole starts a downgrade-only role switch for the user, replacing any
+// switch that is already active.
+func (s *Service) SwitchRole(ctx context.Context, userID uuid.UUID, req *models.SwitchRoleRequest) (*models.RoleInheritance, error) {
+	var originalRole models.Role
+	err := s.db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&originalRole)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if !models.CanInheritRole(originalRole, req.TargetRole) {
+		return nil, fmt.Errorf("%s cannot switch to role %s", originalRole, req.TargetRole)
+	}
+
+	now := time.Now()
+	inheritance := &models.RoleInheritance{
+		ID:            uuid.New(),
+		UserID:        userID,
+		OriginalRole:  originalRole,
+		InheritedRole: req.TargetRole,
+		InheritedAt:   now,
+		IsActive:      true,
+		CreatedAt:     now,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		inheritance.ExpiresAt = &expiresAt
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE role_inheritance SET is_active = FALSE WHERE user_id = $1 AND is_active",
+		userID); err != nil {
+		return nil, fmt.Errorf("failed to deactivate role switch: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO role_inheritance (id, user_id, original_role, inherited_role, inherited_at, expires_at, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		inheritance.ID, inheritance.UserID, inheritance.OriginalRole, inheritance.InheritedRole,
+		inheritance.InheritedAt, inheritance.ExpiresAt, inheritance.IsActive, inheritance.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to switch role: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit role switch: %w", err)
+	}
+
+	s.logger.Info("Role switched",
+		zap.String("user_id", userID.String()),
+		zap.String("original_role", string(originalRole)),
+		zap.String("inherited_role", string(req.TargetRole)))
+
+	return inheritance, nil
+}
+
+// ReturnRole ends the user's active role switch.
+func (s *Service) ReturnRole(ctx context.Context, userID uuid.UUID) (*models.RoleInheritance, error) {
+	var inheritance models.RoleInheritance
+	err := s.db.Pool.QueryRow(ctx, `
+		UPDATE role_inheritance
+		SET is_active = FALSE
+		WHERE user_id = $1 AND is_active
+		RETURNING id, user_id, original_role, inherited_role, inherited_at, expires_at, is_active, created_at`,
+		userID).Scan(
+		&inheritance.ID, &inheritance.UserID, &inheritance.OriginalRole, &inheritance.InheritedRole,
+		&inheritance.InheritedAt, &inheritance.ExpiresAt, &inheritance.IsActive, &inheritance.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("no active role switch found")
+	}
+
+	s.logger.Info("Role switch ended",
+		zap.String("user_id", userID.String()),
+		zap.String("original_role", string(inheritance.OriginalRole)))
+
+	return &inheritance, nil
+}
+
+func (s *Service) GetRoleStatus(ctx context.Context, userID uuid.UUID) (*models.RoleStatusResponse, error) {
+	var originalRole models.Role
+	err := s.db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&originalRole)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	inheritance, err := s.getActiveInheritance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.RoleStatusResponse{
+		OriginalRole:   originalRole,
+		EffectiveRole:  originalRole,
+		IsInheriting:   inheritance != nil,
+		Inheritance:    inheritance,
+		AvailableRoles: models.InheritableRoles(originalRole),
+	}
+	if inheritance != nil {
+		status.EffectiveRole = inheritance.InheritedRole
+	}
+
+	return status, nil
+}
+
+func (s *Service) getActiveInheritance(ctx context.Context, userID uuid.UUID) (*models.RoleInheritance, error) {
+	var inheritance models.RoleInheritance
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, original_role, inherited_role, inherited_at, expires_at, is_active, created_at
+		FROM role_inheritance
+		WHERE user_id = $1 AND is_active`,
+		userID).Scan(
+		&inheritance.ID, &inheritance.UserID, &inheritance.OriginalRole, &inheritance.InheritedRole,
+		&inheritance.InheritedAt, &inheritance.ExpiresAt, &inheritance.IsActive, &inheritance.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role switch: %w", err)
+	}
+
+	if inheritance.ExpiresAt != nil && !time.Now().Before(*inheritance.ExpiresAt) {
+		if _, err := s.db.Pool.Exec(ctx,
+			"UPDATE role_inheritance SET is_active = FALSE WHERE id = $1",
+			inheritance.ID); err != nil {
+			s.logger.Warn("Failed to deactivate expired role switch", zap.Error(err))
+		}
+		return nil, nil
+	}
+
+	return &inheritance, nil
+}
+
+// CreateSession records a new login for the user. The session lives as long
+// as its refresh-token family.
+func (s *Service) CreateSession(ctx context.Context, userID uuid.UUID, userAgent, ipAddress string) (*models.Session, error) {
+	now := time.Now()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  &userAgent,
+		IPAddress:  &ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.config.Auth.RefreshDuration),
+	}
+
+	_, err := s.db.Pool.Exec(ctx, `
+		INSERT INTO user_sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ValidateSession checks that the session has not been revoked or expired
+// and records the request as its last activity.
+func (s *Service) ValidateSession(ctx context.Context, sessionID uuid.UUID) error {
+	now := time.Now()
+	result, err := s.db.Pool.Exec(ctx, `
+		UPDATE user_sessions
+		SET last_seen_at = $1
+		WHERE id = $2 AND revoked_at IS NULL AND expires_at > $1`,
+		now, sessionID)
+
+	if err != nil {
+		return fmt.Errorf("failed to validate session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session revoked or expired")
+	}
+
+	return nil
+}
+
+func (s *Service) GetUserSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC`,
+		userID, time.Now())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's sessions together with its
+// refresh-token family.
+func (s *Service) RevokeSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	result, err := tx.Exec(ctx, `
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`,
+		now, sessionID, userID)
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session not found or access denied")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL`,
+		now, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit session revocation: %w", err)
+	}
+
+	s.logger.Info("Session revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("session_id", sessionID.String()))
+
+	return nil
+}
+
+// RevokeAllSessions revokes every active session of the user and returns how
+// many were revoked.
+func (s *Service) RevokeAllSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	result, err := tx.Exec(ctx, `
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL`,
+		now, userID)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL`,
+		now, userID); err != nil {
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit session revocation: %w", err)
+	}
+
+	s.logger.Info("All sessions revoked",
+		zap.String("user_id", userID.String()),
+		zap.Int64("count", result.RowsAffected()))
+
+	return result.RowsAffected(), nil
+}
+
+// checkImpersonationTarget reports whether the actor may impersonate the
+// target: never themselves, and only users with a lower role.
+func checkImpersonationTarget(actor *models.Actor, target *models.User) error {
+	if target.ID == actor.ID {
+		return fmt.Errorf("cannot impersonate yourself")
+	}
+
+	if target.Role.Level() >= actor.Role.Level() {
+		return fmt.Errorf("cannot impersonate a user with an equal or higher role")
+	}
+
+	return nil
+}
+
+// StartImpersonation opens a session in which the actor acts as the target
+// user. Actors may only impersonate users with a lower role than their own.
+func (s *Service) StartImpersonation(ctx context.Context, actor *models.Actor, req *models.ImpersonateRequest, userAgent, ipAddress string) (*models.User, *models.Session, error) {
+	var target models.User
+	var err error
+	switch {
+	case req.TargetUserID != nil:
+		err = s.db.Pool.QueryRow(ctx,
+			"SELECT id, username, email, role, created_at, updated_at FROM users WHERE id = $1",
+			*req.TargetUserID).Scan(
+			&target.ID, &target.Username, &target.Email, &target.Role, &target.CreatedAt, &target.UpdatedAt)
+	case req.TargetUsername != nil:
+		err = s.db.Pool.QueryRow(ctx,
+			"SELECT id, username, email, role, created_at, updated_at FROM users WHERE username = $1",
+			*req.TargetUsername).Scan(
+			&target.ID, &target.Username, &target.Email, &target.Role, &target.CreatedAt, &target.UpdatedAt)
+	default:
+		return nil, nil, fmt.Errorf("target_user_id or target_username is required")
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("target user not found")
+	}
+
+	if err := checkImpersonationTarget(actor, &target); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.ApplyEffectiveRole(ctx, &target); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     target.ID,
+		UserAgent:  &userAgent,
+		IPAddress:  &ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.config.Auth.ImpersonationDuration),
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `
+		INSERT INTO user_sessions (id, user_id, actor_id, actor_session_id, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		session.ID, session.UserID, actor.ID, actor.SessionID, session.UserAgent, session.IPAddress,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+
+	target.ImpersonatedBy = actor
+
+	s.logger.Info("Impersonation started",
+		zap.String("actor_id", actor.ID.String()),
+		zap.String("actor_username", actor.Username),
+		zap.String("actor_role", string(actor.Role)),
+		zap.String("user_id", target.ID.String()),
+		zap.String("username", target.Username),
+		zap.String("session_id", session.ID.String()))
+
+	return &target, session, nil
+}
+
+// StopImpersonation revokes the impersonation session and returns the actor,
+// ready to be issued a token for their own session again. If the actor's
+// session has ended in the meantime, the impersonation session is still
+// revoked but ErrActorSessionEnded is returned.
+func (s *Service) StopImpersonation(ctx context.Context, subjectID uuid.UUID, sessionID uuid.UUID, actor *models.Actor) (*models.User, error) {
+	actorSessionErr := s.ValidateSession(ctx, actor.SessionID)
+
+	if err := s.RevokeSession(ctx, sessionID, subjectID); err != nil {
+		return nil, err
+	}
+
+	if actorSessionErr != nil {
+		s.logger.Warn("Impersonation stopped after actor session ended",
+			zap.String("actor_id", actor.ID.String()),
+			zap.String("actor_session_id", actor.SessionID.String()),
+			zap.Error(actorSessionErr))
+		return nil, ErrActorSessionEnded
+	}
+
+	var user models.User
+	err := s.db.Pool.QueryRow(ctx,
+		"SELECT id, username, email, role, created_at, updated_at FROM users WHERE id = $1",
+		actor.ID).Scan(
+		&user.ID, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("actor not found")
+	}
+
+	if err := s.ApplyEffectiveRole(ctx, &user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Impersonation stopped",
+		zap.String("actor_id", actor.ID.String()),
+		zap.String("actor_username", actor.Username),
+		zap.String("user_id", subjectID.String()),
+		zap.String("session_id", sessionID.String()))
+
+	return &user, nil
+}
+
+// IssueRefreshToken starts the refresh-token family for a session. It is
+// called once per login; subsequent refreshes rotate within the family.
+func (s *Service) IssueRefreshToken(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (string, time.Time, error) {
+	tokenString, tokenHash, err := generateRefreshToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(s.config.Auth.RefreshDuration)
+	_, err = s.db.Pool.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New(), userID, sessionID, tokenHash, expiresAt, time.Now())
+
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// RotatedRefreshToken is the result of a successful refresh-token rotation.
+type RotatedRefreshToken struct {
+	User         *models.User
+	SessionID    uuid.UUID
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one in the same
+// family and returns the user it belongs to. Presenting a token that has
+// already been rotated is treated as theft: the whole family and its session
+// are revoked and the caller must log in again.
+func (s *Service) RotateRefreshToken(ctx context.Context, tokenString string) (*RotatedRefreshToken, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rotated, err := s.rotateRefreshToken(ctx, tx, tokenString, time.Now())
+	if errors.Is(err, errRefreshTokenReused) {
+		// The revocation must stick even though the exchange fails
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", commitErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+
+	return rotated, nil
+}
+
+func (s *Service) rotateRefreshToken(ctx context.Context, tx pgx.Tx, tokenString string, now time.Time) (*RotatedRefreshToken, error) {
+	var current models.RefreshToken
+	err := tx.QueryRow(ctx, `
+		SELECT id, user_id, family_id, expires_at, rotated_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE`,
+		hashRefreshToken(tokenString)).Scan(
+		&current.ID, &current.UserID, &current.FamilyID, &current.ExpiresAt,
+		&current.RotatedAt, &current.RevokedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if current.RevokedAt != nil {
+		return nil, fmt.Errorf("refresh token revoked")
+	}
+
+	if current.RotatedAt != nil {
+		if _, err := tx.Exec(ctx, `
+			UPDATE refresh_tokens
+			SET revoked_at = $1
+			WHERE family_id = $2 AND revoked_at IS NULL`,
+			now, current.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE user_sessions
+			SET revoked_at = $1
+			WHERE id = $2 AND revoked_at IS NULL`,
+			now, current.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session: %w", err)
+		}
+
+		s.logger.Warn("Refresh token reuse detected, token family revoked",
+			zap.String("user_id", current.UserID.String()),
+			zap.String("family_id", current.FamilyID.String()))
+		return nil, errRefreshTokenReused
+	}
+
+	if now.After(current.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token expired")
+	}
+
+	var user models.User
+	err = tx.QueryRow(ctx,
+		"SELECT id, username, email, role, created_at, updated_at FROM users WHERE id = $1 AND is_active",
+		current.UserID).Scan(
+		&user.ID, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	newTokenString, newTokenHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	newID := uuid.New()
+	expiresAt := now.Add(s.config.Auth.RefreshDuration)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		newID, current.UserID, current.FamilyID, newTokenHash, expiresAt, now); err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET rotated_at = $1, replaced_by = $2
+		WHERE id = $3`,
+		now, newID, current.ID); err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	// The session lives as long as its newest refresh token
+	result, err := tx.Exec(ctx, `
+		UPDATE user_sessions
+		SET expires_at = $1, last_seen_at = $2
+		WHERE id = $3 AND revoked_at IS NULL`,
+		expiresAt, now, current.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extend session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("session revoked")
+	}
+
+	return &RotatedRefreshToken{
+		User:         &user,
+		SessionID:    current.FamilyID,
+		RefreshToken: newTokenString,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
 func (s *Service) CreateAPIToken(ctx context.Context, userID uuid.UUID, req *models.CreateTokenRequest) (*models.APIToken, error) {
 	// Generate random token
 	tokenBytes := make([]byte, 32)
@@ -163,13 +981,18 @@ func (s *Service) CreateAPIToken(ctx context.Context, userID uuid.UUID, req *mod
 	return apiToken, nil
 }
 
-func (s *Service) GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*models.APIToken, error) {
+// GetUserTokens returns one page of the user's API tokens, newest first
+func (s *Service) GetUserTokens(ctx context.Context, userID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.APIToken], error) {
+	cursor, args := params.KeysetCondition([]interface{}{userID})
+	args = append(args, params.Fetch())
+
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT id, user_id, name, token, expires_at, last_used_at, created_at
 		FROM api_tokens 
-		WHERE user_id = $1 
-		ORDER BY created_at DESC`,
-		userID)
+		WHERE user_id = $1 AND `+cursor+`
+		ORDER BY `+pagination.KeysetOrder+`
+		LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user tokens: %w", err)
@@ -187,7 +1010,20 @@ func (s *Service) GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*model
 		tokens = append(tokens, &token)
 	}
 
-	return tokens, nil
+	page := pagination.NewPage(tokens, params, func(last *models.APIToken) pagination.Cursor {
+		return pagination.After(last.CreatedAt, last.ID)
+	})
+
+	if params.IncludeTotal {
+		var total int64
+		err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM api_tokens WHERE user_id = $1", userID).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count user tokens: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
 }
 
 func (s *Service) RevokeToken(ctx context.Context, tokenID uuid.UUID, userID uuid.UUID) error {
@@ -220,7 +1056,7 @@ func (s *Service) ValidateAPIToken(ctx context.Context, tokenString string) (*mo
 		       u.id, u.username, u.email, u.role, u.created_at, u.updated_at
 		FROM api_tokens t
 		JOIN users u ON t.user_id = u.id
-		WHERE t.token = $1`,
+		WHERE t.token = $1 AND u.is_active`,
 		tokenString).Scan(
 		&token.ID, &token.UserID, &token.Name, &token.Token,
 		&token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt,
@@ -246,6 +1082,10 @@ func (s *Service) ValidateAPIToken(ctx context.Context, tokenString string) (*mo
 		s.logger.Warn("Failed to update token last used timestamp", zap.Error(err))
 	}
 
+	if err := s.ApplyEffectiveRole(ctx, &user); err != nil {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
@@ -289,3 +1129,17 @@ func (s *Service) verifyPassword(password, hashedPassword string) bool {
 	return hex.EncodeToString(hash) == hex.EncodeToString(passwordHash)
 }
 
+func generateRefreshToken() (string, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	tokenString := hex.EncodeToString(tokenBytes)
+	return tokenString, hashRefreshToken(tokenString), nil
+}
+
+func hashRefreshToken(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
+
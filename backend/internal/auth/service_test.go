@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"project-management-backend/internal/config"
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func newTestService(maxFailedLogins int, lockout time.Duration) *Service {
+	return &Service{
+		config: &config.Config{Auth: config.AuthConfig{
+			MaxFailedLogins: maxFailedLogins,
+			LockoutDuration: lockout,
+			RefreshDuration: 7 * 24 * time.Hour,
+		}},
+		logger: zap.NewNop(),
+	}
+}
+
+func TestApplyFailedLogin(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	oldReason := "Too many failed login attempts"
+
+	tests := []struct {
+		name          string
+		max           int
+		count         int
+		until         *time.Time
+		wantCount     int
+		wantSuspended bool
+		wantUntil     *time.Time
+	}{
+		{"first failure", 5, 0, nil, 1, false, nil},
+		{"below limit", 5, 3, nil, 4, false, nil},
+		{"reaches limit", 5, 4, nil, 5, true, timePtr(now.Add(24 * time.Hour))},
+		{"past limit", 5, 9, nil, 10, true, timePtr(now.Add(24 * time.Hour))},
+		{"single allowed failure", 1, 0, nil, 1, true, timePtr(now.Add(24 * time.Hour))},
+		{"lapsed suspension starts a new window", 5, 5, &past, 1, false, nil},
+		{"active suspension from a concurrent attempt", 5, 5, &future, 6, false, &future},
+		{"lockout disabled", 0, 100, nil, 101, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(tt.max, 24*time.Hour)
+			user := &models.User{FailedLoginCount: tt.count, SuspendedUntil: tt.until}
+			if tt.until != nil {
+				user.SuspensionReason = &oldReason
+			}
+
+			suspended := s.applyFailedLogin(user, now)
+
+			if suspended != tt.wantSuspended {
+				t.Errorf("suspended = %v, want %v", suspended, tt.wantSuspended)
+			}
+			if user.FailedLoginCount != tt.wantCount {
+				t.Errorf("FailedLoginCount = %d, want %d", user.FailedLoginCount, tt.wantCount)
+			}
+			if !equalTime(user.SuspendedUntil, tt.wantUntil) {
+				t.Errorf("SuspendedUntil = %v, want %v", user.SuspendedUntil, tt.wantUntil)
+			}
+			if (user.SuspendedUntil == nil) != (user.SuspensionReason == nil) {
+				t.Errorf("SuspensionReason = %v with SuspendedUntil = %v", user.SuspensionReason, user.SuspendedUntil)
+			}
+		})
+	}
+}
+
+// TestApplyFailedLoginSequence checks that failures applied one after
+// another, as the row lock serializes them, suspend on the limit exactly
+func TestApplyFailedLoginSequence(t *testing.T) {
+	s := newTestService(3, time.Hour)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.User{}
+
+	var suspensions int
+	for i := 0; i < 10; i++ {
+		if s.applyFailedLogin(user, now) {
+			suspensions++
+		}
+	}
+	if suspensions != 1 || user.FailedLoginCount != 10 {
+		t.Errorf("suspensions = %d, count = %d; want 1 and 10", suspensions, user.FailedLoginCount)
+	}
+	if user.SuspendedUntil == nil || !user.SuspendedUntil.Equal(now.Add(time.Hour)) {
+		t.Errorf("SuspendedUntil = %v", user.SuspendedUntil)
+	}
+
+	// After the lockout the counter starts over
+	later := now.Add(2 * time.Hour)
+	if s.applyFailedLogin(user, later) || user.FailedLoginCount != 1 || user.SuspendedUntil != nil {
+		t.Errorf("after lockout: count = %d, until = %v", user.FailedLoginCount, user.SuspendedUntil)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+
+func TestCheckImpersonationTarget(t *testing.T) {
+	actorID := uuid.New()
+
+	tests := []struct {
+		name       string
+		actorRole  models.Role
+		targetID   uuid.UUID
+		targetRole models.Role
+		wantErr    string
+	}{
+		{"self", models.RoleSuperuser, actorID, models.RoleUser, "cannot impersonate yourself"},
+		{"higher role", models.RoleLocaladmin, uuid.New(), models.RoleSysadmin, "cannot impersonate a user with an equal or higher role"},
+		{"equal role", models.RoleSysadmin, uuid.New(), models.RoleSysadmin, "cannot impersonate a user with an equal or higher role"},
+		{"superuser peer", models.RoleSuperuser, uuid.New(), models.RoleSuperuser, "cannot impersonate a user with an equal or higher role"},
+		{"lower role", models.RoleSysadmin, uuid.New(), models.RoleLocaladmin, ""},
+		{"guest", models.RoleUser, uuid.New(), models.RoleGuest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := &models.Actor{ID: actorID, Role: tt.actorRole}
+			target := &models.User{ID: tt.targetID, Role: tt.targetRole}
+
+			err := checkImpersonationTarget(actor, target)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkImpersonationTarget() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkImpersonationTarget() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
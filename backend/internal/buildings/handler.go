@@ -0,0 +1,245 @@
+package buildings
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ProjectParam is the path parameter that ResolveProject fills with the
+// building's project
+const ProjectParam = "project_id"
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ResolveProject adds the project of the building named by the "id" path
+// parameter as ProjectParam, so that RequireProjectPermission can check the
+// caller's role on that project.
+func (h *Handler) ResolveProject(c *gin.Context) {
+	id, ok := api.ParamID(c, "id", "Invalid building ID")
+	if !ok {
+		c.Abort()
+		return
+	}
+
+	projectID, err := h.service.ProjectID(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, "Failed to get building", err)
+		c.Abort()
+		return
+	}
+
+	c.Params = append(c.Params, gin.Param{Key: ProjectParam, Value: projectID.String()})
+}
+
+// @Summary List project buildings
+// @Description Get the apartment buildings of a project
+// @Tags buildings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param limit query int false "Number of buildings to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of buildings"
+// @Success 200 {object} pagination.Page[models.ApartmentBuilding]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/buildings [get]
+func (h *Handler) ListBuildings(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	buildings, err := h.service.ListBuildings(c.Request.Context(), user, projectID, params)
+	if err != nil {
+		h.respondError(c, "Failed to list buildings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, buildings)
+}
+
+// @Summary Create a building
+// @Description Add an apartment building to a Colony or HousingEstate project
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param request body models.CreateBuildingRequest true "Building data"
+// @Success 201 {object} models.ApartmentBuilding
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id}/buildings [post]
+func (h *Handler) CreateBuilding(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	var req models.CreateBuildingRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	building, err := h.service.CreateBuilding(c.Request.Context(), user, projectID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create building", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, building)
+}
+
+// @Summary Get a building
+// @Description Get apartment building details
+// @Tags buildings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 200 {object} models.ApartmentBuilding
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /buildings/{id} [get]
+func (h *Handler) GetBuilding(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid building ID")
+	if !ok {
+		return
+	}
+
+	building, err := h.service.GetBuilding(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondError(c, "Failed to get building", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, building)
+}
+
+// @Summary Update a building
+// @Description Update apartment building details
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param request body models.UpdateBuildingRequest true "Building update data"
+// @Success 200 {object} models.ApartmentBuilding
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /buildings/{id} [put]
+func (h *Handler) UpdateBuilding(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid building ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBuildingRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	building, err := h.service.UpdateBuilding(c.Request.Context(), user, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update building", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, building)
+}
+
+// @Summary Delete a building
+// @Description Delete an apartment building. Its units must be deleted first.
+// @Tags buildings
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /buildings/{id} [delete]
+func (h *Handler) DeleteBuilding(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid building ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteBuilding(c.Request.Context(), user, id); err != nil {
+		h.respondError(c, "Failed to delete building", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrBuildingNotFound, Status: http.StatusNotFound, Message: "Building not found"},
+		api.ErrorResponse{Err: projects.ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: ErrBuildingsNotAllowed, Status: http.StatusUnprocessableEntity},
+		api.ErrorResponse{Err: ErrBuildingNotEmpty, Status: http.StatusConflict},
+		api.ErrorResponse{Err: projects.ErrNoWriteAccess, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: pagination.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+	)
+}
+
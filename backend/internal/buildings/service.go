@@ -0,0 +1,291 @@
+package buildings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBuildingNotFound    = errors.New("building not found")
+	ErrBuildingsNotAllowed = errors.New("apartment buildings are only allowed in Colony and HousingEstate projects")
+	ErrBuildingNotEmpty    = errors.New("building still has units")
+)
+
+type Service struct {
+	db       *db.Database
+	projects *projects.Service
+	logger   *zap.Logger
+}
+
+func NewService(database *db.Database, projectsSvc *projects.Service, logger *zap.Logger) *Service {
+	return &Service{
+		db:       database,
+		projects: projectsSvc,
+		logger:   logger,
+	}
+}
+
+const buildingColumns = `id, project_id, name, address, floors, created_at, updated_at`
+
+func scanBuilding(row pgx.Row) (*models.ApartmentBuilding, error) {
+	var building models.ApartmentBuilding
+	err := row.Scan(&building.ID, &building.ProjectID, &building.Name, &building.Address, &building.Floors,
+		&building.CreatedAt, &building.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &building, nil
+}
+
+// ProjectID returns the project of a building, so that routes addressing
+// the building directly can be authorized against its project.
+func (s *Service) ProjectID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var projectID uuid.UUID
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT b.project_id
+		FROM apartment_buildings b
+		JOIN projects p ON p.id = b.project_id AND p.deleted_at IS NULL
+		WHERE b.id = $1 AND b.deleted_at IS NULL`, id).Scan(&projectID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrBuildingNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get building project: %w", err)
+	}
+	return projectID, nil
+}
+
+// ListBuildings returns one page of the buildings of a project, ordered by
+// name. The list pages by offset.
+func (s *Service) ListBuildings(ctx context.Context, user *models.User, projectID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.ApartmentBuilding], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+	if _, err := s.projects.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+buildingColumns+`
+		FROM apartment_buildings
+		WHERE project_id = $1 AND deleted_at IS NULL
+		ORDER BY name, id
+		LIMIT $2 OFFSET $3`, projectID, params.Fetch(), params.Offset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buildings: %w", err)
+	}
+	defer rows.Close()
+
+	var buildings []*models.ApartmentBuilding
+	for rows.Next() {
+		building, err := scanBuilding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan building: %w", err)
+		}
+		buildings = append(buildings, building)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list buildings: %w", err)
+	}
+
+	page := pagination.NewPage(buildings, params, pagination.OffsetCursor[*models.ApartmentBuilding](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM apartment_buildings WHERE project_id = $1 AND deleted_at IS NULL",
+			projectID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count buildings: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// GetBuilding returns the building if its project is visible to the user
+func (s *Service) GetBuilding(ctx context.Context, user *models.User, id uuid.UUID) (*models.ApartmentBuilding, error) {
+	building, _, err := s.getBuilding(ctx, user, id)
+	return building, err
+}
+
+func (s *Service) getBuilding(ctx context.Context, user *models.User, id uuid.UUID) (*models.ApartmentBuilding, *models.Project, error) {
+	building, err := scanBuilding(s.db.Pool.QueryRow(ctx, `
+		SELECT `+buildingColumns+`
+		FROM apartment_buildings
+		WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrBuildingNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get building: %w", err)
+	}
+
+	project, err := s.projects.GetProject(ctx, user, building.ProjectID)
+	if err != nil {
+		return nil, nil, ErrBuildingNotFound
+	}
+
+	return building, project, nil
+}
+
+func (s *Service) CreateBuilding(ctx context.Context, user *models.User, projectID uuid.UUID, req *models.CreateBuildingRequest) (*models.ApartmentBuilding, error) {
+	project, err := s.projects.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The lock keeps the project type from changing until the building exists
+	var projectType *string
+	err = tx.QueryRow(ctx,
+		"SELECT type FROM projects WHERE id = $1 AND deleted_at IS NULL FOR SHARE", projectID).Scan(&projectType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, projects.ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock project: %w", err)
+	}
+	if projectType == nil || !models.ProjectType(*projectType).AllowsBuildings() {
+		return nil, ErrBuildingsNotAllowed
+	}
+
+	building := &models.ApartmentBuilding{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      strings.TrimSpace(req.Name),
+		Address:   req.Address,
+		Floors:    req.Floors,
+		CreatedAt: time.Now(),
+	}
+	building.UpdatedAt = building.CreatedAt
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO apartment_buildings (id, project_id, name, address, floors, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		building.ID, building.ProjectID, building.Name, building.Address, building.Floors,
+		building.CreatedAt, building.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create building: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit building creation: %w", err)
+	}
+
+	s.logger.Info("Building created",
+		zap.String("building_id", building.ID.String()),
+		zap.String("project_id", projectID.String()),
+		zap.String("created_by", user.ID.String()))
+	return building, nil
+}
+
+func (s *Service) UpdateBuilding(ctx context.Context, user *models.User, id uuid.UUID, req *models.UpdateBuildingRequest) (*models.ApartmentBuilding, error) {
+	building, err := s.writableBuilding(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		building.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Address != nil {
+		building.Address = req.Address
+	}
+	if req.Floors != nil {
+		building.Floors = req.Floors
+	}
+	building.UpdatedAt = time.Now()
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE apartment_buildings SET name = $2, address = $3, floors = $4, updated_at = $5
+		WHERE id = $1 AND deleted_at IS NULL`,
+		building.ID, building.Name, building.Address, building.Floors, building.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update building: %w", err)
+	}
+
+	return building, nil
+}
+
+// DeleteBuilding removes an empty building. Its units have to be deleted
+// first so that no owned unit disappears with it.
+func (s *Service) DeleteBuilding(ctx context.Context, user *models.User, id uuid.UUID) error {
+	if _, err := s.writableBuilding(ctx, user, id); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Units are created under a share lock on their building, so none can
+	// be added between this check and the delete
+	var buildingID uuid.UUID
+	err = tx.QueryRow(ctx,
+		"SELECT id FROM apartment_buildings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&buildingID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrBuildingNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock building: %w", err)
+	}
+
+	var hasUnits bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM houses WHERE building_id = $1 AND deleted_at IS NULL)", id).Scan(&hasUnits)
+	if err != nil {
+		return fmt.Errorf("failed to check building units: %w", err)
+	}
+	if hasUnits {
+		return ErrBuildingNotEmpty
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE apartment_buildings SET deleted_at = NOW() WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete building: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit building deletion: %w", err)
+	}
+
+	s.logger.Info("Building deleted",
+		zap.String("building_id", id.String()),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
+// writableBuilding returns the building if the user may change its project
+func (s *Service) writableBuilding(ctx context.Context, user *models.User, id uuid.UUID) (*models.ApartmentBuilding, error) {
+	building, project, err := s.getBuilding(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+	return building, nil
+}
+
@@ -4,20 +4,24 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
-	OTEL     OTELConfig
-	RBAC     RBACConfig
-	Logging  LoggingConfig
-	Security SecurityConfig
-	Metrics  MetricsConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Auth        AuthConfig
+	OTEL        OTELConfig
+	RBAC        RBACConfig
+	Projects    ProjectsConfig
+	Storage     StorageConfig
+	Attachments AttachmentsConfig
+	Logging     LoggingConfig
+	Security    SecurityConfig
+	Metrics     MetricsConfig
 }
 
 type ServerConfig struct {
@@ -44,6 +48,10 @@ type AuthConfig struct {
 	SessionDuration time.Duration
 	TokenDuration   time.Duration
 	RefreshDuration time.Duration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+
+	ImpersonationDuration time.Duration
 }
 
 type OTELConfig struct {
@@ -52,8 +60,43 @@ type OTELConfig struct {
 }
 
 type RBACConfig struct {
-	ModelPath  string
-	PolicyPath string
+	ModelPath      string
+	PolicyPath     string
+	PolicySource   string
+	ReloadInterval time.Duration
+}
+
+type ProjectsConfig struct {
+	TrashRetention time.Duration
+	PurgeInterval  time.Duration
+	// WorkflowPath is a JSON status workflow; the built-in one is used when empty
+	WorkflowPath string
+}
+
+// StorageConfig selects the blob store for uploaded files: "local" keeps
+// them under LocalPath, "s3" in an S3-compatible bucket such as MinIO
+type StorageConfig struct {
+	Backend   string
+	LocalPath string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PublicEndpoint is the endpoint clients use for signed download
+	// links, when it differs from the one the server talks to
+	S3PublicEndpoint string
+}
+
+type AttachmentsConfig struct {
+	MaxSize      int64
+	AllowedTypes []string
+	// LinkTTL is how long signed download links stay valid
+	LinkTTL time.Duration
+	// SigningKey signs download links served by the API; defaults to the JWT secret
+	SigningKey      string
+	CleanupInterval time.Duration
 }
 
 type LoggingConfig struct {
@@ -81,7 +124,7 @@ func Load() (*Config, error) {
 	// Load environment file based on environment
 	env := getEnv("ENVIRONMENT", "development")
 	envFile := fmt.Sprintf("env.%s", env)
-	
+
 	// Try to load environment-specific file first
 	if err := godotenv.Load(envFile); err != nil {
 		// Fallback to .env file
@@ -113,14 +156,42 @@ func Load() (*Config, error) {
 			SessionDuration: getDurationEnv("SESSION_DURATION", 24*time.Hour),
 			TokenDuration:   getDurationEnv("TOKEN_DURATION", 4*time.Hour),
 			RefreshDuration: getDurationEnv("REFRESH_DURATION", 7*24*time.Hour),
+			MaxFailedLogins: getIntEnv("MAX_FAILED_LOGINS", 5),
+			LockoutDuration: getDurationEnv("LOCKOUT_DURATION", 24*time.Hour),
+
+			ImpersonationDuration: getDurationEnv("IMPERSONATION_DURATION", 2*time.Hour),
 		},
 		OTEL: OTELConfig{
 			Endpoint: getEnv("OTEL_ENDPOINT", "http://localhost:4318/v1/traces"),
 			Enabled:  getBoolEnv("OTEL_ENABLED", true),
 		},
 		RBAC: RBACConfig{
-			ModelPath:  getEnv("RBAC_MODEL_PATH", "./rbac_model.conf"),
-			PolicyPath: getEnv("RBAC_POLICY_PATH", "./rbac_policy.csv"),
+			ModelPath:      getEnv("RBAC_MODEL_PATH", "./rbac_model.conf"),
+			PolicyPath:     getEnv("RBAC_POLICY_PATH", "./rbac_policy.csv"),
+			PolicySource:   getEnv("RBAC_POLICY_SOURCE", "file"),
+			ReloadInterval: getDurationEnv("RBAC_RELOAD_INTERVAL", time.Minute),
+		},
+		Projects: ProjectsConfig{
+			TrashRetention: getDurationEnv("PROJECT_TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval:  getDurationEnv("PROJECT_PURGE_INTERVAL", time.Hour),
+			WorkflowPath:   getEnv("PROJECT_WORKFLOW_PATH", ""),
+		},
+		Storage: StorageConfig{
+			Backend:          getEnv("STORAGE_BACKEND", "local"),
+			LocalPath:        getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+			S3Endpoint:       getEnv("S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:         getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:         getEnv("S3_BUCKET", "attachments"),
+			S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+			S3PublicEndpoint: getEnv("S3_PUBLIC_ENDPOINT", ""),
+		},
+		Attachments: AttachmentsConfig{
+			MaxSize:         getInt64Env("ATTACHMENT_MAX_SIZE", 25<<20),
+			AllowedTypes:    getListEnv("ATTACHMENT_ALLOWED_TYPES", defaultAttachmentTypes),
+			LinkTTL:         getDurationEnv("ATTACHMENT_LINK_TTL", 5*time.Minute),
+			SigningKey:      getEnv("ATTACHMENT_SIGNING_KEY", ""),
+			CleanupInterval: getDurationEnv("ATTACHMENT_CLEANUP_INTERVAL", 10*time.Minute),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -142,9 +213,26 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if config.Attachments.SigningKey == "" {
+		config.Attachments.SigningKey = config.Auth.JWTSecret
+	}
+
 	return config, nil
 }
 
+// defaultAttachmentTypes are the content types accepted for uploads unless
+// ATTACHMENT_ALLOWED_TYPES is set. "image/*" matches any image type.
+var defaultAttachmentTypes = []string{
+	"application/pdf",
+	"image/*",
+	"text/plain",
+	"text/csv",
+	"application/zip",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -152,6 +240,24 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
 func getInt32Env(key string, defaultValue int32) int32 {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.ParseInt(value, 10, 32); err == nil {
@@ -161,6 +267,21 @@ func getInt32Env(key string, defaultValue int32) int32 {
 	return defaultValue
 }
 
+// getListEnv reads a comma-separated list
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
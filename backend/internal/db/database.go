@@ -7,7 +7,7 @@ import (
 
 	"project-management-backend/internal/config"
 
-	"github.com/jackc/pgxpool/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
@@ -0,0 +1,372 @@
+package houses
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/buildings"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ProjectParam is the path parameter that ResolveProject fills with the
+// house's project
+const ProjectParam = "project_id"
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ResolveProject adds the project of the house named by the "id" path
+// parameter as ProjectParam, so that RequireProjectPermission can check the
+// caller's role on that project.
+func (h *Handler) ResolveProject(c *gin.Context) {
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		c.Abort()
+		return
+	}
+
+	projectID, err := h.service.ProjectID(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, "Failed to get house", err)
+		c.Abort()
+		return
+	}
+
+	c.Params = append(c.Params, gin.Param{Key: ProjectParam, Value: projectID.String()})
+}
+
+// @Summary List project houses
+// @Description Get the standalone houses and building units of a project
+// @Tags houses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param building_id query string false "Only the units of this building"
+// @Param limit query int false "Number of houses to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of houses"
+// @Success 200 {object} pagination.Page[models.House]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/houses [get]
+func (h *Handler) ListHouses(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	var buildingID *uuid.UUID
+	if value := c.Query("building_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+			return
+		}
+		buildingID = &id
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	houses, err := h.service.ListHouses(c.Request.Context(), user, projectID, buildingID, params)
+	if err != nil {
+		h.respondError(c, "Failed to list houses", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, houses)
+}
+
+// @Summary List building units
+// @Description Get the units of an apartment building
+// @Tags houses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param limit query int false "Number of units to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of units"
+// @Success 200 {object} pagination.Page[models.House]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /buildings/{id}/units [get]
+func (h *Handler) ListUnits(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	buildingID, ok := api.ParamID(c, "id", "Invalid building ID")
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	units, err := h.service.ListUnits(c.Request.Context(), user, buildingID, params)
+	if err != nil {
+		h.respondError(c, "Failed to list units", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, units)
+}
+
+// @Summary Create a house or unit
+// @Description Add a standalone house to a project, or a unit of one of its buildings when building_id is set. Units need unit_number, category_type and size_sqm.
+// @Tags houses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param request body models.CreateHouseRequest true "House data"
+// @Success 201 {object} models.House
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id}/houses [post]
+func (h *Handler) CreateHouse(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectID, ok := api.ParamID(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	var req models.CreateHouseRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	house, err := h.service.CreateHouse(c.Request.Context(), user, projectID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create house", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, house)
+}
+
+// @Summary Get a house
+// @Description Get a house or unit with its owners and building
+// @Tags houses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "House ID"
+// @Success 200 {object} models.HouseDetail
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /houses/{id} [get]
+func (h *Handler) GetHouse(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		return
+	}
+
+	house, err := h.service.GetHouse(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondError(c, "Failed to get house", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, house)
+}
+
+// @Summary Update a house
+// @Description Update house or unit details
+// @Tags houses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "House ID"
+// @Param request body models.UpdateHouseRequest true "House update data"
+// @Success 200 {object} models.House
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /houses/{id} [put]
+func (h *Handler) UpdateHouse(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateHouseRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	house, err := h.service.UpdateHouse(c.Request.Context(), user, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update house", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, house)
+}
+
+// @Summary Delete a house
+// @Description Delete a house or unit
+// @Tags houses
+// @Security BearerAuth
+// @Param id path string true "House ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /houses/{id} [delete]
+func (h *Handler) DeleteHouse(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteHouse(c.Request.Context(), user, id); err != nil {
+		h.respondError(c, "Failed to delete house", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List house owners
+// @Description Get the owners of a house with their percentage shares
+// @Tags houses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "House ID"
+// @Success 200 {array} models.HouseOwner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /houses/{id}/owners [get]
+func (h *Handler) GetHouseOwners(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		return
+	}
+
+	owners, err := h.service.ListOwners(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondError(c, "Failed to list house owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owners)
+}
+
+// @Summary Replace house owners
+// @Description Reassign a house to owners whose percentages add up to 100. The previous owners are replaced atomically.
+// @Tags houses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "House ID"
+// @Param request body models.SetHouseOwnersRequest true "New owners and their shares"
+// @Success 200 {array} models.HouseOwner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /houses/{id}/owners [put]
+func (h *Handler) SetHouseOwners(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid house ID")
+	if !ok {
+		return
+	}
+
+	var req models.SetHouseOwnersRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	owners, err := h.service.SetOwners(c.Request.Context(), user, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update house owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owners)
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrHouseNotFound, Status: http.StatusNotFound, Message: "House not found"},
+		api.ErrorResponse{Err: buildings.ErrBuildingNotFound, Status: http.StatusNotFound, Message: "Building not found"},
+		api.ErrorResponse{Err: projects.ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: ErrOwnerNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrUnitNumberTaken, Status: http.StatusConflict},
+		api.ErrorResponse{Err: ErrInvalidOwnerShares, Status: http.StatusUnprocessableEntity},
+		api.ErrorResponse{Err: projects.ErrNoWriteAccess, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: pagination.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+	)
+}
+
@@ -0,0 +1,150 @@
+package houses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOwnerNotFound      = errors.New("owner not found")
+	ErrInvalidOwnerShares = errors.New("house owners must be at least one owner whose percentages add up to 100")
+)
+
+// ownerSharesConstraint is raised by the deferred trigger on house_owners
+// when a transaction commits an invalid set of shares.
+const ownerSharesConstraint = "house_owners_shares"
+
+func (s *Service) ListOwners(ctx context.Context, user *models.User, houseID uuid.UUID) ([]*models.HouseOwner, error) {
+	if _, _, err := s.getHouse(ctx, user, houseID); err != nil {
+		return nil, err
+	}
+	return s.listOwners(ctx, houseID)
+}
+
+func (s *Service) listOwners(ctx context.Context, houseID uuid.UUID) ([]*models.HouseOwner, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT ho.house_id, ho.owner_id, ho.percentage, ho.role, ho.created_at, ho.updated_at,
+		       o.id, o.name, o.email, o.phone, o.metadata, o.created_at, o.updated_at
+		FROM house_owners ho
+		JOIN owners o ON o.id = ho.owner_id
+		WHERE ho.house_id = $1
+		ORDER BY ho.role = 'primary' DESC, ho.percentage DESC, o.name`, houseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list house owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := []*models.HouseOwner{}
+	for rows.Next() {
+		var share models.HouseOwner
+		var owner models.Owner
+		if err := rows.Scan(&share.HouseID, &share.OwnerID, &share.Percentage, &share.Role,
+			&share.CreatedAt, &share.UpdatedAt, &owner.ID, &owner.Name, &owner.Email, &owner.Phone,
+			&owner.Metadata, &owner.CreatedAt, &owner.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan house owner: %w", err)
+		}
+		share.Owner = &owner
+		owners = append(owners, &share)
+	}
+
+	return owners, rows.Err()
+}
+
+// SetOwners replaces all owners of a house in one transaction. As for
+// projects, the database checks the shares again at commit.
+func (s *Service) SetOwners(ctx context.Context, user *models.User, houseID uuid.UUID, req *models.SetHouseOwnersRequest) ([]*models.HouseOwner, error) {
+	if _, err := s.writableHouse(ctx, user, houseID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, "SELECT id FROM houses WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", houseID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrHouseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock house: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM house_owners WHERE house_id = $1", houseID); err != nil {
+		return nil, fmt.Errorf("failed to clear house owners: %w", err)
+	}
+	if err := insertOwners(ctx, tx, houseID, req.Owners); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, ownerSharesError(err, "failed to commit house owners")
+	}
+
+	s.logger.Info("House owners updated",
+		zap.String("house_id", houseID.String()),
+		zap.Int("owners", len(req.Owners)),
+		zap.String("updated_by", user.ID.String()))
+
+	return s.listOwners(ctx, houseID)
+}
+
+// insertOwners adds the shares to the house. The owners are locked so they
+// can't be removed from the registry before the transaction commits.
+func insertOwners(ctx context.Context, tx pgx.Tx, houseID uuid.UUID, shares []models.OwnerShareInput) error {
+	ids := make([]uuid.UUID, len(shares))
+	for i, share := range shares {
+		ids[i] = share.OwnerID
+	}
+
+	var found int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM owners WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE
+		) locked`, ids).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to check owners: %w", err)
+	}
+	if found != len(ids) {
+		return ErrOwnerNotFound
+	}
+
+	now := time.Now()
+	for _, share := range shares {
+		role := share.Role
+		if role == "" {
+			role = models.OwnerRoleCoOwner
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO house_owners (house_id, owner_id, percentage, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)`,
+			houseID, share.OwnerID, share.Percentage, role, now)
+		if err != nil {
+			return ownerSharesError(err, "failed to add house owner")
+		}
+	}
+
+	return nil
+}
+
+// ownerSharesError maps a violation of the share rules to
+// ErrInvalidOwnerShares and wraps anything else.
+func ownerSharesError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == ownerSharesConstraint {
+		return fmt.Errorf("%w: %s", ErrInvalidOwnerShares, pgErr.Message)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
@@ -0,0 +1,340 @@
+package houses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/buildings"
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/projects"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrHouseNotFound   = errors.New("house not found")
+	ErrUnitNumberTaken = errors.New("unit number is already used in this building")
+)
+
+// unitNumberIndex keeps unit numbers unique within a building
+const unitNumberIndex = "idx_houses_building_unit"
+
+type Service struct {
+	db        *db.Database
+	projects  *projects.Service
+	buildings *buildings.Service
+	logger    *zap.Logger
+}
+
+func NewService(database *db.Database, projectsSvc *projects.Service, buildingsSvc *buildings.Service, logger *zap.Logger) *Service {
+	return &Service{
+		db:        database,
+		projects:  projectsSvc,
+		buildings: buildingsSvc,
+		logger:    logger,
+	}
+}
+
+const houseColumns = `id, project_id, building_id, unit_number, name, category_type, size_sqm,
+		       metadata, created_at, updated_at`
+
+func scanHouse(row pgx.Row) (*models.House, error) {
+	var house models.House
+	err := row.Scan(&house.ID, &house.ProjectID, &house.BuildingID, &house.UnitNumber, &house.Name,
+		&house.CategoryType, &house.SizeSqm, &house.Metadata, &house.CreatedAt, &house.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &house, nil
+}
+
+// ProjectID returns the project of a house, so that routes addressing the
+// house directly can be authorized against its project.
+func (s *Service) ProjectID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var projectID uuid.UUID
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT h.project_id
+		FROM houses h
+		JOIN projects p ON p.id = h.project_id AND p.deleted_at IS NULL
+		WHERE h.id = $1 AND h.deleted_at IS NULL`, id).Scan(&projectID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrHouseNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get house project: %w", err)
+	}
+	return projectID, nil
+}
+
+// ListHouses returns one page of the houses and units of a project,
+// optionally only those of one building
+func (s *Service) ListHouses(ctx context.Context, user *models.User, projectID uuid.UUID, buildingID *uuid.UUID, params *pagination.Params) (*pagination.Page[*models.House], error) {
+	if _, err := s.projects.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	where := "project_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{projectID}
+	if buildingID != nil {
+		args = append(args, *buildingID)
+		where += " AND building_id = $2"
+	}
+
+	return s.pageHouses(ctx, where, args, "building_id NULLS FIRST, unit_number, name, id", params)
+}
+
+// ListUnits returns one page of the units of a building
+func (s *Service) ListUnits(ctx context.Context, user *models.User, buildingID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.House], error) {
+	if _, err := s.buildings.GetBuilding(ctx, user, buildingID); err != nil {
+		return nil, err
+	}
+
+	return s.pageHouses(ctx, "building_id = $1 AND deleted_at IS NULL",
+		[]interface{}{buildingID}, "unit_number, id", params)
+}
+
+// pageHouses returns one page of the houses matching where in the given
+// order. House lists page by offset.
+func (s *Service) pageHouses(ctx context.Context, where string, args []interface{}, order string, params *pagination.Params) (*pagination.Page[*models.House], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	filterArgs := args
+	args = append(args, params.Fetch(), params.Offset())
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+houseColumns+`
+		FROM houses
+		WHERE `+where+`
+		ORDER BY `+order+`
+		LIMIT `+fmt.Sprintf("$%d OFFSET $%d", len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list houses: %w", err)
+	}
+	defer rows.Close()
+
+	var houses []*models.House
+	for rows.Next() {
+		house, err := scanHouse(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan house: %w", err)
+		}
+		houses = append(houses, house)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list houses: %w", err)
+	}
+
+	page := pagination.NewPage(houses, params, pagination.OffsetCursor[*models.House](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM houses WHERE "+where, filterArgs...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count houses: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// GetHouse returns the house with its owners and, for a unit, its building
+func (s *Service) GetHouse(ctx context.Context, user *models.User, id uuid.UUID) (*models.HouseDetail, error) {
+	house, _, err := s.getHouse(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &models.HouseDetail{House: *house}
+	if detail.Owners, err = s.listOwners(ctx, id); err != nil {
+		return nil, err
+	}
+	if house.IsUnit() {
+		if detail.Building, err = s.buildings.GetBuilding(ctx, user, *house.BuildingID); err != nil {
+			return nil, err
+		}
+	}
+
+	return detail, nil
+}
+
+func (s *Service) getHouse(ctx context.Context, user *models.User, id uuid.UUID) (*models.House, *models.Project, error) {
+	house, err := scanHouse(s.db.Pool.QueryRow(ctx, `
+		SELECT `+houseColumns+`
+		FROM houses
+		WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrHouseNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get house: %w", err)
+	}
+
+	project, err := s.projects.GetProject(ctx, user, house.ProjectID)
+	if err != nil {
+		return nil, nil, ErrHouseNotFound
+	}
+
+	return house, project, nil
+}
+
+// writableHouse returns the house if the user may change its project
+func (s *Service) writableHouse(ctx context.Context, user *models.User, id uuid.UUID) (*models.House, error) {
+	house, project, err := s.getHouse(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+	return house, nil
+}
+
+// CreateHouse adds a standalone house to the project, or a unit when the
+// request names one of the project's buildings
+func (s *Service) CreateHouse(ctx context.Context, user *models.User, projectID uuid.UUID, req *models.CreateHouseRequest) (*models.House, error) {
+	project, err := s.projects.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projects.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if req.BuildingID != nil {
+		// The share lock keeps the building from being deleted before the
+		// unit is committed
+		var buildingID uuid.UUID
+		err := tx.QueryRow(ctx, `
+			SELECT id FROM apartment_buildings
+			WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL
+			FOR SHARE`, *req.BuildingID, projectID).Scan(&buildingID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, buildings.ErrBuildingNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock building: %w", err)
+		}
+	}
+
+	house := &models.House{
+		ID:           uuid.New(),
+		ProjectID:    projectID,
+		BuildingID:   req.BuildingID,
+		UnitNumber:   req.UnitNumber,
+		Name:         strings.TrimSpace(req.Name),
+		CategoryType: req.CategoryType,
+		SizeSqm:      req.SizeSqm,
+		Metadata:     models.JSONB(req.Metadata),
+		CreatedAt:    time.Now(),
+	}
+	house.UpdatedAt = house.CreatedAt
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO houses (
+			id, project_id, building_id, unit_number, name, category_type, size_sqm,
+			metadata, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		house.ID, house.ProjectID, house.BuildingID, house.UnitNumber, house.Name,
+		house.CategoryType, house.SizeSqm, house.Metadata, house.CreatedAt, house.UpdatedAt)
+	if err != nil {
+		return nil, houseWriteError(err, "failed to create house")
+	}
+
+	if len(req.Owners) > 0 {
+		if err := insertOwners(ctx, tx, house.ID, req.Owners); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, ownerSharesError(err, "failed to commit house creation")
+	}
+
+	s.logger.Info("House created",
+		zap.String("house_id", house.ID.String()),
+		zap.String("project_id", projectID.String()),
+		zap.String("created_by", user.ID.String()))
+	return house, nil
+}
+
+func (s *Service) UpdateHouse(ctx context.Context, user *models.User, id uuid.UUID, req *models.UpdateHouseRequest) (*models.House, error) {
+	house, err := s.writableHouse(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UnitNumber != nil {
+		house.UnitNumber = req.UnitNumber
+	}
+	if req.Name != nil {
+		house.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.CategoryType != nil {
+		house.CategoryType = req.CategoryType
+	}
+	if req.SizeSqm != nil {
+		house.SizeSqm = req.SizeSqm
+	}
+	if req.Metadata != nil {
+		house.Metadata = models.JSONB(req.Metadata)
+	}
+	house.UpdatedAt = time.Now()
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE houses
+		SET unit_number = $2, name = $3, category_type = $4, size_sqm = $5, metadata = $6, updated_at = $7
+		WHERE id = $1 AND deleted_at IS NULL`,
+		house.ID, house.UnitNumber, house.Name, house.CategoryType, house.SizeSqm, house.Metadata, house.UpdatedAt)
+	if err != nil {
+		return nil, houseWriteError(err, "failed to update house")
+	}
+
+	return house, nil
+}
+
+func (s *Service) DeleteHouse(ctx context.Context, user *models.User, id uuid.UUID) error {
+	if _, err := s.writableHouse(ctx, user, id); err != nil {
+		return err
+	}
+
+	result, err := s.db.Pool.Exec(ctx,
+		"UPDATE houses SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete house: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrHouseNotFound
+	}
+
+	s.logger.Info("House deleted",
+		zap.String("house_id", id.String()),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
+// houseWriteError maps a duplicate unit number to ErrUnitNumberTaken
+func houseWriteError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == unitNumberIndex {
+		return ErrUnitNumberTaken
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
@@ -0,0 +1,165 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// jsonEqual compares two JSON documents by value
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result is not JSON: %s", got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expectation %s: %v", want, err)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7396 appendix A
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s): %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, got, tt.want) {
+			t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+
+	if _, err := MergePatch([]byte(`{}`), []byte(`{`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("malformed merge patch error = %v, want ErrInvalidPatch", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	// Mostly the examples of RFC 6902 appendix A
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"add member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add array element", `{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"add at array end index", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux"]}`},
+		{"add with dash index", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"add null value", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`},
+		{"add nested member", `{"foo":{"bar":[1,{"x":1}]}}`,
+			`[{"op":"add","path":"/foo/bar/1/y","value":2}]`, `{"foo":{"bar":[1,{"x":1,"y":2}]}}`},
+		{"add replaces whole document", `{"foo":1}`,
+			`[{"op":"add","path":"","value":[1]}]`, `[1]`},
+		{"remove member", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"replace array element", `{"foo":[1,2]}`,
+			`[{"op":"replace","path":"/foo/0","value":3}]`, `{"foo":[3,2]}`},
+		{"move member", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"move to itself", `{"foo":{"bar":1}}`,
+			`[{"op":"move","from":"/foo","path":"/foo"}]`, `{"foo":{"bar":1}}`},
+		{"move to sibling with shared prefix", `{"a":1}`,
+			`[{"op":"move","from":"/a","path":"/ab"}]`, `{"ab":1}`},
+		{"copy is independent", `{"foo":{"bar":1}}`,
+			`[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			`{"foo":{"bar":1},"baz":{"bar":2}}`},
+		{"test passes", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{"escaped tokens", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10},{"op":"replace","path":"/~1","value":0}]`,
+			`{"/":0,"~1":10}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("Apply = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, patch string
+		want             error
+	}{
+		{"malformed patch", `{}`, `{"op":"add"}`, ErrInvalidPatch},
+		{"unknown op", `{}`, `[{"op":"merge","path":"/a","value":1}]`, ErrInvalidPatch},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, ErrInvalidPatch},
+		{"relative path", `{}`, `[{"op":"add","path":"a","value":1}]`, ErrInvalidPatch},
+		{"add to missing parent", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrInvalidPatch},
+		{"add past array end", `{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":2}]`, ErrInvalidPatch},
+		{"add to scalar", `{"foo":1}`, `[{"op":"add","path":"/foo/bar","value":2}]`, ErrInvalidPatch},
+		{"remove missing member", `{"foo":1}`, `[{"op":"remove","path":"/bar"}]`, ErrInvalidPatch},
+		{"remove with dash index", `{"foo":[1]}`, `[{"op":"remove","path":"/foo/-"}]`, ErrInvalidPatch},
+		{"remove whole document", `{"foo":1}`, `[{"op":"remove","path":""}]`, ErrInvalidPatch},
+		{"replace missing member", `{"foo":1}`, `[{"op":"replace","path":"/bar","value":2}]`, ErrInvalidPatch},
+		{"leading zero index", `{"foo":[1,2]}`, `[{"op":"replace","path":"/foo/01","value":3}]`, ErrInvalidPatch},
+		{"signed index", `{"foo":[1,2]}`, `[{"op":"replace","path":"/foo/+1","value":3}]`, ErrInvalidPatch},
+		{"negative index", `{"foo":[1,2]}`, `[{"op":"remove","path":"/foo/-1"}]`, ErrInvalidPatch},
+		{"move into own child", `{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`, ErrInvalidPatch},
+		{"move from missing", `{}`, `[{"op":"move","from":"/a","path":"/b"}]`, ErrInvalidPatch},
+		{"test fails", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ErrTestFailed},
+		{"test number against string", `{"baz":"1"}`, `[{"op":"test","path":"/baz","value":1}]`, ErrTestFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply([]byte(tt.doc), []byte(tt.patch)); !errors.Is(err, tt.want) {
+				t.Errorf("Apply error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	doc := []byte(`{"a":1}`)
+	_, err := Apply(doc, []byte(`[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":3}]`))
+	if !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("Apply error = %v, want ErrTestFailed", err)
+	}
+	if string(doc) != `{"a":1}` {
+		t.Errorf("document changed to %s", doc)
+	}
+}
+
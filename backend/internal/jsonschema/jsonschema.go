@@ -0,0 +1,503 @@
+// Package jsonschema validates JSON values against a subset of JSON Schema
+// (draft 2020-12). Schemas using keywords outside the subset are rejected
+// when compiled rather than silently ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidSchema is wrapped by the errors Compile returns
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// Types are the JSON Schema type names
+var Types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Formats are the supported values of the "format" keyword
+var Formats = []string{"date", "date-time", "email", "uri"}
+
+// annotations are keywords that describe a schema without constraining it
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// Schema is a compiled schema
+type Schema struct {
+	Types                []string
+	Format               string
+	Title                string
+	Description          string
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema
+	// NoAdditionalProperties is set by "additionalProperties": false
+	NoAdditionalProperties bool
+	Items                  *Schema
+	Enum                   []interface{}
+	Const                  interface{}
+	HasConst               bool
+	Minimum                *float64
+	Maximum                *float64
+	ExclusiveMinimum       *float64
+	ExclusiveMaximum       *float64
+	MultipleOf             *float64
+	MinLength              *int
+	MaxLength              *int
+	Pattern                *regexp.Regexp
+	MinItems               *int
+	MaxItems               *int
+	UniqueItems            bool
+}
+
+// Error is one failed constraint. Path is the location of the offending
+// value, with dots between object members and [i] for array items; it is
+// empty for the root value.
+type Error struct {
+	Path    string
+	Keyword string
+	Message string
+}
+
+// Compile parses and checks a schema document
+func Compile(document []byte) (*Schema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(document, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return compile(raw, "")
+}
+
+func compile(raw interface{}, path string) (*Schema, error) {
+	if value, ok := raw.(bool); ok {
+		// true accepts anything; false accepts nothing
+		if value {
+			return &Schema{}, nil
+		}
+		return &Schema{Types: []string{}}, nil
+	}
+
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(path, "must be an object or a boolean")
+	}
+
+	schema := &Schema{}
+	keywords := make([]string, 0, len(object))
+	for keyword := range object {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		value := object[keyword]
+		at := join(path, keyword)
+		var err error
+
+		switch keyword {
+		case "type":
+			schema.Types, err = compileTypes(value, at)
+		case "format":
+			schema.Format, err = stringValue(value, at)
+			if err == nil && !contains(Formats, schema.Format) {
+				err = schemaError(at, fmt.Sprintf("unsupported format %q, expected one of %s", schema.Format, strings.Join(Formats, ", ")))
+			}
+		case "title":
+			schema.Title, err = stringValue(value, at)
+		case "description":
+			schema.Description, err = stringValue(value, at)
+		case "properties":
+			schema.Properties, err = compileProperties(value, at)
+		case "required":
+			schema.Required, err = stringList(value, at)
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				schema.NoAdditionalProperties = !allowed
+			} else {
+				schema.AdditionalProperties, err = compile(value, at)
+			}
+		case "items":
+			schema.Items, err = compile(value, at)
+		case "enum":
+			list, ok := value.([]interface{})
+			if !ok || len(list) == 0 {
+				err = schemaError(at, "must be a non-empty array")
+			}
+			schema.Enum = list
+		case "const":
+			schema.Const, schema.HasConst = value, true
+		case "minimum":
+			schema.Minimum, err = number(value, at, false)
+		case "maximum":
+			schema.Maximum, err = number(value, at, false)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = number(value, at, false)
+		case "exclusiveMaximum":
+			schema.ExclusiveMaximum, err = number(value, at, false)
+		case "multipleOf":
+			schema.MultipleOf, err = number(value, at, true)
+		case "minLength":
+			schema.MinLength, err = count(value, at)
+		case "maxLength":
+			schema.MaxLength, err = count(value, at)
+		case "minItems":
+			schema.MinItems, err = count(value, at)
+		case "maxItems":
+			schema.MaxItems, err = count(value, at)
+		case "pattern":
+			var pattern string
+			if pattern, err = stringValue(value, at); err == nil {
+				if schema.Pattern, err = regexp.Compile(pattern); err != nil {
+					err = schemaError(at, "must be a valid regular expression")
+				}
+			}
+		case "uniqueItems":
+			if schema.UniqueItems, ok = value.(bool); !ok {
+				err = schemaError(at, "must be a boolean")
+			}
+		default:
+			if !annotations[keyword] {
+				err = schemaError(at, "unsupported keyword")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+func compileTypes(value interface{}, path string) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		list, err := stringList(v, path)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, schemaError(path, "must not be empty")
+		}
+		types = list
+	default:
+		return nil, schemaError(path, "must be a type name or an array of them")
+	}
+
+	for _, name := range types {
+		if !contains(Types, name) {
+			return nil, schemaError(path, fmt.Sprintf("unknown type %q", name))
+		}
+	}
+	return types, nil
+}
+
+func compileProperties(value interface{}, path string) (map[string]*Schema, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(path, "must be an object")
+	}
+
+	properties := make(map[string]*Schema, len(object))
+	for name, raw := range object {
+		property, err := compile(raw, join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = property
+	}
+	return properties, nil
+}
+
+func stringValue(value interface{}, path string) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", schemaError(path, "must be a string")
+	}
+	return s, nil
+}
+
+func stringList(value interface{}, path string) ([]string, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, schemaError(path, "must be an array of strings")
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, schemaError(path, "must be an array of strings")
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+func number(value interface{}, path string, positive bool) (*float64, error) {
+	n, ok := value.(float64)
+	if !ok || (positive && n <= 0) {
+		if positive {
+			return nil, schemaError(path, "must be a positive number")
+		}
+		return nil, schemaError(path, "must be a number")
+	}
+	return &n, nil
+}
+
+func count(value interface{}, path string) (*int, error) {
+	n, ok := value.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) || n > math.MaxInt32 {
+		return nil, schemaError(path, "must be a non-negative integer")
+	}
+	c := int(n)
+	return &c, nil
+}
+
+func schemaError(path, message string) error {
+	if path == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidSchema, message)
+	}
+	return fmt.Errorf("%w: %s %s", ErrInvalidSchema, path, message)
+}
+
+// Validate checks a decoded JSON value (as produced by encoding/json into
+// an interface{}) and returns every failed constraint
+func (s *Schema) Validate(value interface{}) []Error {
+	var errs []Error
+	s.validate(value, "", &errs)
+	return errs
+}
+
+func (s *Schema) validate(value interface{}, path string, errs *[]Error) {
+	fail := func(keyword, message string) {
+		*errs = append(*errs, Error{Path: path, Keyword: keyword, Message: message})
+	}
+
+	if s.Types != nil && !s.matchesType(value) {
+		if len(s.Types) == 0 {
+			fail("false", "is not allowed")
+		} else {
+			fail("type", "must be of type "+strings.Join(s.Types, " or "))
+		}
+		// The remaining keywords assume the right type
+		return
+	}
+
+	if s.HasConst && !equal(value, s.Const) {
+		fail("const", "must be "+display(s.Const))
+	}
+	if s.Enum != nil && !s.inEnum(value) {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			values = append(values, display(v))
+		}
+		fail("enum", "must be one of "+strings.Join(values, ", "))
+	}
+
+	switch v := value.(type) {
+	case float64:
+		s.validateNumber(v, fail)
+	case string:
+		s.validateString(v, fail)
+	case []interface{}:
+		s.validateArray(v, path, errs, fail)
+	case map[string]interface{}:
+		s.validateObject(v, path, errs, fail)
+	}
+}
+
+func (s *Schema) matchesType(value interface{}) bool {
+	for _, name := range s.Types {
+		if typeOf(value) == name || (name == "number" && typeOf(value) == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf names the JSON type of a value, reporting whole numbers as integer
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+func (s *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if equal(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateNumber(n float64, fail func(string, string)) {
+	if s.Minimum != nil && n < *s.Minimum {
+		fail("minimum", "must be at least "+formatNumber(*s.Minimum))
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		fail("maximum", "must be at most "+formatNumber(*s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+		fail("exclusiveMinimum", "must be greater than "+formatNumber(*s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+		fail("exclusiveMaximum", "must be less than "+formatNumber(*s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil {
+		quotient := n / *s.MultipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("multipleOf", "must be a multiple of "+formatNumber(*s.MultipleOf))
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, fail func(string, string)) {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("minLength", fmt.Sprintf("must be at least %d characters", *s.MinLength))
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("maxLength", fmt.Sprintf("must be at most %d characters", *s.MaxLength))
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(str) {
+		fail("pattern", "must match "+s.Pattern.String())
+	}
+	if s.Format != "" && !validFormat(s.Format, str) {
+		fail("format", "must be a valid "+s.Format)
+	}
+}
+
+func validFormat(format, str string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err == nil
+	case "email":
+		address, err := mail.ParseAddress(str)
+		return err == nil && address.Address == str
+	case "uri":
+		u, err := url.Parse(str)
+		return err == nil && u.Scheme != ""
+	default:
+		return true
+	}
+}
+
+func (s *Schema) validateArray(items []interface{}, path string, errs *[]Error, fail func(string, string)) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		fail("minItems", fmt.Sprintf("must have at least %d items", *s.MinItems))
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		fail("maxItems", fmt.Sprintf("must have at most %d items", *s.MaxItems))
+	}
+	if s.UniqueItems {
+	unique:
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if equal(items[i], items[j]) {
+					fail("uniqueItems", "must not contain duplicates")
+					break unique
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range items {
+			s.Items.validate(item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+func (s *Schema) validateObject(object map[string]interface{}, path string, errs *[]Error, fail func(string, string)) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, Error{Path: join(path, name), Keyword: "required", Message: "is required"})
+		}
+	}
+
+	// Sorted so errors come out in a stable order
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		at := join(path, name)
+		if property, ok := s.Properties[name]; ok {
+			property.validate(object[name], at, errs)
+			continue
+		}
+		if s.NoAdditionalProperties {
+			*errs = append(*errs, Error{Path: at, Keyword: "additionalProperties", Message: "is not allowed"})
+		} else if s.AdditionalProperties != nil {
+			s.AdditionalProperties.validate(object[name], at, errs)
+		}
+	}
+}
+
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func display(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		message  string
+	}{
+		{"not JSON", `{`, "invalid schema"},
+		{"not an object", `"string"`, "must be an object or a boolean"},
+		{"unknown keyword", `{"oneOf": []}`, "oneOf unsupported keyword"},
+		{"nested unknown keyword", `{"properties": {"a": {"$ref": "#"}}}`, "properties.a.$ref unsupported keyword"},
+		{"empty type array", `{"type": []}`, "type must not be empty"},
+		{"unknown type", `{"type": "float"}`, `unknown type "float"`},
+		{"type not a string", `{"type": 1}`, "type must be a type name"},
+		{"type array of non-strings", `{"type": ["string", 1]}`, "type must be an array of strings"},
+		{"unsupported format", `{"format": "ipv4"}`, `unsupported format "ipv4"`},
+		{"properties not an object", `{"properties": []}`, "properties must be an object"},
+		{"required not strings", `{"required": [1]}`, "required must be an array of strings"},
+		{"empty enum", `{"enum": []}`, "enum must be a non-empty array"},
+		{"minimum not a number", `{"minimum": "1"}`, "minimum must be a number"},
+		{"zero multipleOf", `{"multipleOf": 0}`, "multipleOf must be a positive number"},
+		{"negative minLength", `{"minLength": -1}`, "minLength must be a non-negative integer"},
+		{"fractional maxItems", `{"maxItems": 1.5}`, "maxItems must be a non-negative integer"},
+		{"invalid pattern", `{"pattern": "("}`, "pattern must be a valid regular expression"},
+		{"uniqueItems not a boolean", `{"uniqueItems": 1}`, "uniqueItems must be a boolean"},
+		{"invalid items", `{"items": 1}`, "items must be an object or a boolean"},
+		{"invalid additionalProperties", `{"additionalProperties": {"type": "x"}}`, `additionalProperties.type unknown type "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.document))
+			if !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("Compile() error = %v, want ErrInvalidSchema", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Compile() error = %q, want it to contain %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestCompileAcceptsAnnotations(t *testing.T) {
+	document := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Metadata",
+		"description": "Extra fields",
+		"default": {},
+		"examples": [{}],
+		"type": ["object", "null"]
+	}`
+	schema, err := Compile([]byte(document))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if schema.Title != "Metadata" || !reflect.DeepEqual(schema.Types, []string{"object", "null"}) {
+		t.Errorf("Compile() = %+v", schema)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string
+	}{
+		{"true accepts anything", `true`, `{"a": [1]}`, nil},
+		{"false rejects everything", `false`, `null`, []string{":false"}},
+		{"type", `{"type": "string"}`, `1`, []string{":type"}},
+		{"integer is a number", `{"type": "number"}`, `3`, nil},
+		{"number is not an integer", `{"type": "integer"}`, `3.5`, []string{":type"}},
+		{"whole float is an integer", `{"type": "integer"}`, `3.0`, nil},
+		{"type list", `{"type": ["string", "null"]}`, `null`, nil},
+		{"wrong type skips other keywords", `{"type": "string", "minLength": 5}`, `1`, []string{":type"}},
+		{"enum", `{"enum": ["a", 1, null]}`, `"b"`, []string{":enum"}},
+		{"enum match", `{"enum": ["a", 1, null]}`, `1`, nil},
+		{"enum compares objects", `{"enum": [{"a": [1]}]}`, `{"a": [1]}`, nil},
+		{"const", `{"const": 5}`, `6`, []string{":const"}},
+
+		{"minimum", `{"minimum": 1}`, `0`, []string{":minimum"}},
+		{"minimum inclusive", `{"minimum": 1}`, `1`, nil},
+		{"maximum", `{"maximum": 1}`, `1.5`, []string{":maximum"}},
+		{"exclusiveMinimum", `{"exclusiveMinimum": 1}`, `1`, []string{":exclusiveMinimum"}},
+		{"exclusiveMaximum", `{"exclusiveMaximum": 1}`, `1`, []string{":exclusiveMaximum"}},
+		{"multipleOf", `{"multipleOf": 0.5}`, `1.25`, []string{":multipleOf"}},
+		{"multipleOf decimal", `{"multipleOf": 0.1}`, `0.3`, nil},
+
+		{"minLength counts characters", `{"minLength": 3}`, `"äö"`, []string{":minLength"}},
+		{"maxLength", `{"maxLength": 2}`, `"abc"`, []string{":maxLength"}},
+		{"pattern", `{"pattern": "^[A-Z]{2}-\\d+$"}`, `"ab-1"`, []string{":pattern"}},
+		{"pattern match", `{"pattern": "^[A-Z]{2}-\\d+$"}`, `"AB-12"`, nil},
+
+		{"format date", `{"format": "date"}`, `"2024-02-30"`, []string{":format"}},
+		{"format date valid", `{"format": "date"}`, `"2024-02-29"`, nil},
+		{"format date-time", `{"format": "date-time"}`, `"2024-01-01 10:00"`, []string{":format"}},
+		{"format date-time valid", `{"format": "date-time"}`, `"2024-01-01T10:00:00+02:00"`, nil},
+		{"format email", `{"format": "email"}`, `"Bob <bob@example.com>"`, []string{":format"}},
+		{"format email valid", `{"format": "email"}`, `"bob@example.com"`, nil},
+		{"format uri", `{"format": "uri"}`, `"example.com/page"`, []string{":format"}},
+		{"format uri valid", `{"format": "uri"}`, `"https://example.com/page"`, nil},
+		{"format ignores other types", `{"format": "email"}`, `5`, nil},
+
+		{"minItems", `{"minItems": 2}`, `[1]`, []string{":minItems"}},
+		{"maxItems", `{"maxItems": 1}`, `[1, 2]`, []string{":maxItems"}},
+		{"uniqueItems", `{"uniqueItems": true}`, `[1, 2, 1, 2]`, []string{":uniqueItems"}},
+		{"uniqueItems objects", `{"uniqueItems": true}`, `[{"a": 1}, {"a": 1}]`, []string{":uniqueItems"}},
+		{"items paths", `{"type": "object", "properties": {"tags": {"items": {"type": "string"}}}}`, `{"tags": ["a", 2, "c", false]}`, []string{"tags[1]:type", "tags[3]:type"}},
+		{"nested items paths", `{"items": {"items": {"minimum": 0}}}`, `[[0], [1, -1]]`, []string{"[1][1]:minimum"}},
+
+		{"required", `{"required": ["name", "size"]}`, `{"name": "x"}`, []string{"size:required"}},
+		{"nested required", `{"properties": {"owner": {"required": ["email"]}}}`, `{"owner": {}}`, []string{"owner.email:required"}},
+		{"additionalProperties false", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"a": 1, "c": 2, "b": 3}`, []string{"b:additionalProperties", "c:additionalProperties"}},
+		{"additionalProperties schema", `{"properties": {"a": {}}, "additionalProperties": {"type": "number"}}`, `{"a": "x", "b": "y"}`, []string{"b:type"}},
+		{"additionalProperties default", `{"properties": {"a": {"type": "number"}}}`, `{"a": 1, "b": "y"}`, nil},
+		{"several errors in order", `{
+			"type": "object",
+			"required": ["id"],
+			"properties": {
+				"area": {"type": "number", "minimum": 0},
+				"rooms": {"type": "array", "items": {"type": "object", "properties": {"name": {"minLength": 1}}}}
+			}
+		}`, `{"area": -5, "rooms": [{"name": "Kitchen"}, {"name": ""}]}`, []string{"id:required", "area:minimum", "rooms[1].name:minLength"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Compile([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatalf("invalid instance: %v", err)
+			}
+
+			var got []string
+			for _, e := range schema.Validate(instance) {
+				if e.Message == "" {
+					t.Errorf("error at %q has no message", e.Path)
+				}
+				got = append(got, e.Path+":"+e.Keyword)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMessages(t *testing.T) {
+	schema, err := Compile([]byte(`{"type": ["string", "null"], "enum": ["a", null], "maxLength": 3}`))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if errs := schema.Validate(1.0); len(errs) != 1 || errs[0].Message != "must be of type string or null" {
+		t.Errorf("Validate(1) = %+v", errs)
+	}
+	if errs := schema.Validate("abcd"); len(errs) != 2 || errs[0].Message != `must be one of "a", null` || errs[1].Message != "must be at most 3 characters" {
+		t.Errorf("Validate(abcd) = %+v", errs)
+	}
+}
+
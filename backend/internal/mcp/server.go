@@ -6,26 +6,45 @@ import (
 	"net/http"
 	"time"
 
+	"project-management-backend/internal/attachments"
 	"project-management-backend/internal/auth"
+	"project-management-backend/internal/buildings"
 	"project-management-backend/internal/config"
 	"project-management-backend/internal/db"
+	"project-management-backend/internal/houses"
 	"project-management-backend/internal/middleware"
+	"project-management-backend/internal/organizations"
+	"project-management-backend/internal/owners"
 	"project-management-backend/internal/projects"
+	"project-management-backend/internal/rbac"
+	"project-management-backend/internal/storage"
+	"project-management-backend/internal/users"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	config      *config.Config
-	logger      *zap.Logger
-	database    *db.Database
-	authSvc     *auth.Service
-	projectsSvc *projects.Service
-	router      *gin.Engine
-	server      *http.Server
+	config          *config.Config
+	logger          *zap.Logger
+	database        *db.Database
+	authSvc         *auth.Service
+	projectsSvc     *projects.Service
+	usersSvc        *users.Service
+	orgsSvc         *organizations.Service
+	ownersSvc       *owners.Service
+	buildingsSvc    *buildings.Service
+	housesSvc       *houses.Service
+	attachmentsSvc  *attachments.Service
+	enforcer        *casbin.SyncedEnforcer
+	stopPurge       context.CancelFunc
+	stopBlobCleanup context.CancelFunc
+	router          *gin.Engine
+	server          *http.Server
 }
 
 func NewServer(cfg *config.Config, logger *zap.Logger) (*Server, error) {
@@ -38,6 +57,33 @@ func NewServer(cfg *config.Config, logger *zap.Logger) (*Server, error) {
 	// Initialize services
 	authSvc := auth.NewService(database, cfg, logger)
 	projectsSvc := projects.NewService(database, logger)
+	if cfg.Projects.WorkflowPath != "" {
+		workflow, err := projects.LoadWorkflow(cfg.Projects.WorkflowPath)
+		if err != nil {
+			database.Close()
+			return nil, fmt.Errorf("failed to load project workflow: %w", err)
+		}
+		projectsSvc.SetWorkflow(workflow)
+	}
+	usersSvc := users.NewService(database, logger)
+	orgsSvc := organizations.NewService(database, logger)
+	ownersSvc := owners.NewService(database, logger)
+	buildingsSvc := buildings.NewService(database, projectsSvc, logger)
+	housesSvc := houses.NewService(database, projectsSvc, buildingsSvc, logger)
+
+	blobStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to initialize attachment storage: %w", err)
+	}
+	attachmentsSvc := attachments.NewService(database, projectsSvc, blobStore, cfg.Attachments, logger)
+
+	// Initialize authorization
+	enforcer, err := rbac.NewEnforcer(cfg, database, logger)
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to initialize RBAC: %w", err)
+	}
 
 	// Initialize router
 	router := gin.New()
@@ -50,12 +96,19 @@ func NewServer(cfg *config.Config, logger *zap.Logger) (*Server, error) {
 
 	// Initialize server
 	srv := &Server{
-		config:      cfg,
-		logger:      logger,
-		database:    database,
-		authSvc:     authSvc,
-		projectsSvc: projectsSvc,
-		router:      router,
+		config:         cfg,
+		logger:         logger,
+		database:       database,
+		authSvc:        authSvc,
+		projectsSvc:    projectsSvc,
+		usersSvc:       usersSvc,
+		orgsSvc:        orgsSvc,
+		ownersSvc:      ownersSvc,
+		buildingsSvc:   buildingsSvc,
+		housesSvc:      housesSvc,
+		attachmentsSvc: attachmentsSvc,
+		enforcer:       enforcer,
+		router:         router,
 	}
 
 	// Setup routes
@@ -77,10 +130,16 @@ func (s *Server) setupRoutes() {
 			authHandler := auth.NewHandler(s.authSvc, s.logger)
 			authGroup.POST("/signup", authHandler.Signup)
 			authGroup.POST("/login", authHandler.Login)
+			authGroup.POST("/refresh", authHandler.Refresh)
 		}
 
+		attachmentsHandler := attachments.NewHandler(s.attachmentsSvc, s.logger)
+
+		// Signed attachment download links carry their own authorization
+		api.GET("/attachments/:id/content", attachmentsHandler.DownloadSigned)
+
 		// Protected routes
-		authMiddleware := middleware.NewAuthMiddleware(s.config.Auth.JWTSecret, s.logger)
+		authMiddleware := middleware.NewAuthMiddleware(s.config.Auth.JWTSecret, s.authSvc, s.enforcer, s.projectsSvc, s.logger)
 		protected := api.Group("/")
 		protected.Use(authMiddleware.RequireAuth())
 		{
@@ -93,26 +152,161 @@ func (s *Server) setupRoutes() {
 				authGroup.POST("/tokens", authHandler.CreateToken)
 				authGroup.GET("/tokens", authHandler.GetTokens)
 				authGroup.DELETE("/tokens/:id", authHandler.RevokeToken)
+				authGroup.GET("/sessions", authHandler.GetSessions)
+				authGroup.DELETE("/sessions", authHandler.RevokeAllSessions)
+				authGroup.DELETE("/sessions/:id", authHandler.RevokeSession)
+				authGroup.POST("/switch-role", authHandler.SwitchRole)
+				authGroup.POST("/return-role", authHandler.ReturnRole)
+				authGroup.GET("/role-status", authHandler.GetRoleStatus)
+				authGroup.POST("/stop-impersonation", authHandler.StopImpersonation)
+				authGroup.GET("/impersonation-status", authHandler.GetImpersonationStatus)
+
+				adminAuth := authGroup.Group("")
+				adminAuth.Use(authMiddleware.RequireRole("localadmin"))
+				{
+					adminAuth.POST("/unlock/:userId", authHandler.UnlockUser)
+				}
+
+				impersonation := authGroup.Group("")
+				impersonation.Use(authMiddleware.RequireRole("sysadmin"))
+				{
+					impersonation.POST("/impersonate", authHandler.Impersonate)
+				}
+			}
+
+			// User management routes (admin only)
+			usersGroup := protected.Group("/users")
+			{
+				usersHandler := users.NewHandler(s.usersSvc, s.logger)
+				usersGroup.GET("", authMiddleware.RequirePermission("users", "read"), usersHandler.ListUsers)
+				usersGroup.GET("/:id", authMiddleware.RequirePermission("users/:id", "read"), usersHandler.GetUser)
+				usersGroup.PUT("/:id", authMiddleware.RequirePermission("users/:id", "update"), usersHandler.UpdateUser)
+				usersGroup.DELETE("/:id", authMiddleware.RequirePermission("users/:id", "delete"), usersHandler.DeleteUser)
+				usersGroup.POST("/:id/deactivate", authMiddleware.RequirePermission("users/:id", "update"), usersHandler.DeactivateUser)
+				usersGroup.POST("/:id/activate", authMiddleware.RequirePermission("users/:id", "update"), usersHandler.ActivateUser)
+			}
+
+			// Organization routes. Organization roles are checked by the service.
+			orgsHandler := organizations.NewHandler(s.orgsSvc, s.logger)
+			orgsGroup := protected.Group("/organizations")
+			{
+				orgsGroup.GET("", orgsHandler.ListOrganizations)
+				orgsGroup.POST("", authMiddleware.RequirePermission("organizations", "create"), orgsHandler.CreateOrganization)
+				orgsGroup.GET("/:id", orgsHandler.GetOrganization)
+				orgsGroup.PUT("/:id", orgsHandler.UpdateOrganization)
+				orgsGroup.DELETE("/:id", orgsHandler.DeleteOrganization)
+				orgsGroup.GET("/:id/members", orgsHandler.ListMembers)
+				orgsGroup.PUT("/:id/members/:userId", orgsHandler.UpdateMember)
+				orgsGroup.DELETE("/:id/members/:userId", orgsHandler.RemoveMember)
+				orgsGroup.GET("/:id/invitations", orgsHandler.ListInvitations)
+				orgsGroup.POST("/:id/invitations", orgsHandler.CreateInvitation)
+				orgsGroup.DELETE("/:id/invitations/:invitationId", orgsHandler.RevokeInvitation)
+			}
+			protected.POST("/invitations/accept", orgsHandler.AcceptInvitation)
+
+			// Owners registry routes
+			ownersGroup := protected.Group("/owners")
+			{
+				ownersHandler := owners.NewHandler(s.ownersSvc, s.logger)
+				ownersGroup.GET("", authMiddleware.RequirePermission("owners", "read"), ownersHandler.ListOwners)
+				ownersGroup.POST("", authMiddleware.RequirePermission("owners", "create"), ownersHandler.CreateOwner)
+				ownersGroup.GET("/:id", authMiddleware.RequirePermission("owners/:id", "read"), ownersHandler.GetOwner)
+				ownersGroup.PUT("/:id", authMiddleware.RequirePermission("owners/:id", "update"), ownersHandler.UpdateOwner)
+				ownersGroup.DELETE("/:id", authMiddleware.RequirePermission("owners/:id", "delete"), ownersHandler.DeleteOwner)
+			}
+
+			// RBAC administration routes
+			rbacGroup := protected.Group("/rbac")
+			{
+				rbacHandler := rbac.NewHandler(s.enforcer, s.logger)
+				rbacGroup.POST("/reload", authMiddleware.RequirePermission("rbac", "reload"), rbacHandler.ReloadPolicy)
 			}
 
+			buildingsHandler := buildings.NewHandler(s.buildingsSvc, s.logger)
+			housesHandler := houses.NewHandler(s.housesSvc, s.logger)
+
 			// Projects routes
 			projectsGroup := protected.Group("/projects")
 			{
 				projectsHandler := projects.NewHandler(s.projectsSvc, s.logger)
 
-				// Public project routes (all authenticated users)
-				projectsGroup.GET("", projectsHandler.ListProjects)
-				projectsGroup.GET("/stats", projectsHandler.GetProjectStats)
-				projectsGroup.GET("/:id", projectsHandler.GetProject)
-
-				// Admin-only project routes
-				adminProjects := projectsGroup.Group("")
-				adminProjects.Use(authMiddleware.RequireRole("localadmin"))
+				// Read routes (all authenticated users by default policy)
+				readProjects := projectsGroup.Group("")
+				readProjects.Use(authMiddleware.RequirePermission("projects", "read"))
 				{
-					adminProjects.POST("", projectsHandler.CreateProject)
-					adminProjects.PUT("/:id", projectsHandler.UpdateProject)
-					adminProjects.DELETE("/:id", projectsHandler.DeleteProject)
+					readProjects.GET("", projectsHandler.ListProjects)
+					readProjects.GET("/stats", projectsHandler.GetProjectStats)
+					readProjects.GET("/analytics", projectsHandler.GetProjectAnalytics)
+					readProjects.GET("/export.geojson", projectsHandler.ExportGeoJSON)
+					readProjects.GET("/export.kml", projectsHandler.ExportKML)
+					readProjects.GET("/metadata-schemas", projectsHandler.ListMetadataSchemas)
+					readProjects.GET("/metadata-schemas/:type", projectsHandler.GetMetadataSchema)
+					readProjects.GET("/:id", projectsHandler.GetProject)
+					readProjects.GET("/:id/versions", projectsHandler.ListVersions)
+					readProjects.GET("/:id/versions/diff", projectsHandler.DiffVersions)
+					readProjects.GET("/:id/versions/:version", projectsHandler.GetVersion)
+					readProjects.GET("/:id/transitions", projectsHandler.ListTransitions)
+					readProjects.GET("/:id/status-history", projectsHandler.StatusHistory)
+					readProjects.GET("/:id/owners", projectsHandler.GetProjectOwners)
+					readProjects.GET("/:id/buildings", buildingsHandler.ListBuildings)
+					readProjects.GET("/:id/houses", housesHandler.ListHouses)
+					readProjects.GET("/:id/attachments", attachmentsHandler.ListAttachments)
+					readProjects.GET("/:id/attachments/:attachmentId", attachmentsHandler.GetAttachment)
+					readProjects.GET("/:id/attachments/:attachmentId/download", attachmentsHandler.DownloadAttachment)
 				}
+
+				// Write routes (localadmin and above, or project admins and
+				// sub-admins of the targeted project, by default policy)
+				projectsGroup.POST("", authMiddleware.RequirePermission("projects", "create"), projectsHandler.CreateProject)
+				projectsGroup.PUT("/:id", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), projectsHandler.UpdateProject)
+				projectsGroup.PATCH("/:id", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), projectsHandler.PatchProject)
+				projectsGroup.DELETE("/:id", authMiddleware.RequireProjectPermission("id", "projects/:id", "delete"), projectsHandler.DeleteProject)
+				// Metadata schemas (localadmin and above by default policy)
+				projectsGroup.PUT("/metadata-schemas/:type", authMiddleware.RequirePermission("projects/metadata-schemas", "update"), projectsHandler.SetMetadataSchema)
+				projectsGroup.DELETE("/metadata-schemas/:type", authMiddleware.RequirePermission("projects/metadata-schemas", "delete"), projectsHandler.DeleteMetadataSchema)
+				// Trash routes
+				projectsGroup.GET("/trash", authMiddleware.RequirePermission("projects/trash", "read"), projectsHandler.ListTrash)
+				projectsGroup.POST("/:id/restore", authMiddleware.RequireProjectPermission("id", "projects/:id", "delete"), projectsHandler.RestoreProject)
+				projectsGroup.DELETE("/:id/purge", authMiddleware.RequireProjectPermission("id", "projects/:id", "purge"), projectsHandler.PurgeProject)
+
+				projectsGroup.PUT("/:id/owners", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), projectsHandler.SetProjectOwners)
+				projectsGroup.POST("/:id/buildings", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), buildingsHandler.CreateBuilding)
+				projectsGroup.POST("/:id/houses", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), housesHandler.CreateHouse)
+				projectsGroup.POST("/:id/attachments", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), attachmentsHandler.UploadAttachment)
+				projectsGroup.DELETE("/:id/attachments/:attachmentId", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), attachmentsHandler.DeleteAttachment)
+
+				projectsGroup.POST("/:id/versions/:version/restore", authMiddleware.RequireProjectPermission("id", "projects/:id", "update"), projectsHandler.RestoreVersion)
+
+				// Status changes, checked per transition as "transition/<name>"
+				projectsGroup.POST("/:id/transitions/:transition", authMiddleware.RequireProjectPermission("id", "projects/:id", "transition/:transition"), projectsHandler.TransitionProject)
+
+				// Project membership routes
+				projectsGroup.GET("/:id/users", authMiddleware.RequireProjectPermission("id", "projects/:id/users", "read"), projectsHandler.ListMembers)
+				projectsGroup.POST("/:id/users", authMiddleware.RequireProjectPermission("id", "projects/:id/users", "manage"), projectsHandler.AddMember)
+				projectsGroup.PUT("/:id/users/:userId", authMiddleware.RequireProjectPermission("id", "projects/:id/users", "manage"), projectsHandler.UpdateMember)
+				projectsGroup.DELETE("/:id/users/:userId", authMiddleware.RequireProjectPermission("id", "projects/:id/users", "manage"), projectsHandler.RemoveMember)
+			}
+
+			// Buildings and houses addressed directly are authorized against
+			// their project, which ResolveProject adds to the path parameters.
+			// Changing them counts as updating the project.
+			buildingsGroup := protected.Group("/buildings")
+			{
+				updateBuildingProject := authMiddleware.RequireProjectPermission(buildings.ProjectParam, "projects/:"+buildings.ProjectParam, "update")
+				buildingsGroup.GET("/:id", authMiddleware.RequirePermission("projects", "read"), buildingsHandler.GetBuilding)
+				buildingsGroup.GET("/:id/units", authMiddleware.RequirePermission("projects", "read"), housesHandler.ListUnits)
+				buildingsGroup.PUT("/:id", buildingsHandler.ResolveProject, updateBuildingProject, buildingsHandler.UpdateBuilding)
+				buildingsGroup.DELETE("/:id", buildingsHandler.ResolveProject, updateBuildingProject, buildingsHandler.DeleteBuilding)
+			}
+
+			housesGroup := protected.Group("/houses")
+			{
+				updateHouseProject := authMiddleware.RequireProjectPermission(houses.ProjectParam, "projects/:"+houses.ProjectParam, "update")
+				housesGroup.GET("/:id", authMiddleware.RequirePermission("projects", "read"), housesHandler.GetHouse)
+				housesGroup.GET("/:id/owners", authMiddleware.RequirePermission("projects", "read"), housesHandler.GetHouseOwners)
+				housesGroup.PUT("/:id", housesHandler.ResolveProject, updateHouseProject, housesHandler.UpdateHouse)
+				housesGroup.DELETE("/:id", housesHandler.ResolveProject, updateHouseProject, housesHandler.DeleteHouse)
+				housesGroup.PUT("/:id/owners", housesHandler.ResolveProject, updateHouseProject, housesHandler.SetHouseOwners)
 			}
 		}
 	}
@@ -150,6 +344,18 @@ func (s *Server) Start() error {
 		WriteTimeout: s.config.Server.WriteTimeout,
 	}
 
+	if s.config.Projects.TrashRetention > 0 && s.config.Projects.PurgeInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopPurge = cancel
+		go s.projectsSvc.RunTrashPurge(ctx, s.config.Projects.TrashRetention, s.config.Projects.PurgeInterval)
+	}
+
+	if s.config.Attachments.CleanupInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopBlobCleanup = cancel
+		go s.attachmentsSvc.RunBlobCleanup(ctx, s.config.Attachments.CleanupInterval)
+	}
+
 	s.logger.Info("Starting MCP server",
 		zap.String("host", s.config.Server.Host),
 		zap.String("port", s.config.Server.Port),
@@ -167,6 +373,18 @@ func (s *Server) Stop(ctx context.Context) error {
 		}
 	}
 
+	if s.stopPurge != nil {
+		s.stopPurge()
+	}
+
+	if s.stopBlobCleanup != nil {
+		s.stopBlobCleanup()
+	}
+
+	if s.enforcer != nil {
+		s.enforcer.StopAutoLoadPolicy()
+	}
+
 	if s.database != nil {
 		s.database.Close()
 	}
@@ -177,8 +395,9 @@ func (s *Server) Stop(ctx context.Context) error {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, If-Match, If-None-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -9,25 +10,58 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// TokenValidator resolves opaque API tokens to the user that owns them and
+// checks that the session behind a session token is still active.
+// It is satisfied by auth.Service.
+type TokenValidator interface {
+	ValidateAPIToken(ctx context.Context, tokenString string) (*models.User, error)
+	ValidateSession(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// PermissionEnforcer decides whether a subject may perform an action on a
+// resource. It is satisfied by casbin enforcers.
+type PermissionEnforcer interface {
+	Enforce(rvals ...interface{}) (bool, error)
+}
+
+// ProjectRoleResolver returns a user's role on a project, or "" if the user
+// is not a member. It is satisfied by projects.Service.
+type ProjectRoleResolver interface {
+	GetMemberRole(ctx context.Context, projectID, userID uuid.UUID) (string, error)
+}
+
 type AuthMiddleware struct {
-	jwtSecret string
-	logger    *zap.Logger
+	jwtSecret      string
+	tokenValidator TokenValidator
+	enforcer       PermissionEnforcer
+	projectRoles   ProjectRoleResolver
+	logger         *zap.Logger
 }
 
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	OriginalRole string `json:"original_role,omitempty"`
+
+	ActorID        string `json:"actor_id,omitempty"`
+	ActorUsername  string `json:"actor_username,omitempty"`
+	ActorRole      string `json:"actor_role,omitempty"`
+	ActorSessionID string `json:"actor_session_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewAuthMiddleware(jwtSecret string, logger *zap.Logger) *AuthMiddleware {
+func NewAuthMiddleware(jwtSecret string, tokenValidator TokenValidator, enforcer PermissionEnforcer, projectRoles ProjectRoleResolver, logger *zap.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: jwtSecret,
-		logger:    logger,
+		jwtSecret:      jwtSecret,
+		tokenValidator: tokenValidator,
+		enforcer:       enforcer,
+		projectRoles:   projectRoles,
+		logger:         logger,
 	}
 }
 
@@ -40,23 +74,113 @@ func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := a.validateToken(tokenString)
-		if err != nil {
-			a.logger.Warn("Invalid token", zap.Error(err))
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
+		var user *models.User
+		var authMethod string
+		if isJWT(tokenString) {
+			claims, err := a.validateToken(tokenString)
+			if err != nil {
+				a.logger.Warn("Invalid token", zap.Error(err))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			userID, err := uuid.Parse(claims.UserID)
+			if err != nil {
+				a.logger.Warn("Invalid user ID in token", zap.String("user_id", claims.UserID))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			sessionID, err := uuid.Parse(claims.ID)
+			if err != nil {
+				a.logger.Warn("Session token without session ID", zap.String("user_id", claims.UserID))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			if a.tokenValidator != nil {
+				if err := a.tokenValidator.ValidateSession(c.Request.Context(), sessionID); err != nil {
+					a.logger.Warn("Rejected session", zap.String("session_id", claims.ID), zap.Error(err))
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+					c.Abort()
+					return
+				}
+			}
+
+			// Role holds the effective role; it differs from OriginalRole only
+			// while a role switch is active
+			user = &models.User{
+				ID:       userID,
+				Username: claims.Username,
+				Role:     models.Role(claims.Role),
+			}
+			if claims.OriginalRole != "" {
+				originalRole := models.Role(claims.OriginalRole)
+				user.OriginalRole = &originalRole
+			}
+			if claims.ActorID != "" {
+				actor, err := actorFromClaims(claims)
+				if err != nil {
+					a.logger.Warn("Invalid actor in impersonation token", zap.Error(err))
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+					c.Abort()
+					return
+				}
+				user.ImpersonatedBy = actor
+			}
+			authMethod = "session"
+			c.Set("session_id", claims.ID)
+		} else {
+			if a.tokenValidator == nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			// ValidateAPIToken also records last_used_at for the token
+			apiUser, err := a.tokenValidator.ValidateAPIToken(c.Request.Context(), tokenString)
+			if err != nil {
+				a.logger.Warn("Invalid API token", zap.Error(err))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			user = apiUser
+			authMethod = "api_token"
 		}
 
 		// Set user context
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-		c.Set("user", &models.User{
-			ID:       claims.UserID,
-			Username: claims.Username,
-			Role:     models.Role(claims.Role),
-		})
+		c.Set("user_id", user.ID.String())
+		c.Set("username", user.Username)
+		c.Set("role", string(user.Role))
+		if user.OriginalRole != nil {
+			c.Set("original_role", string(*user.OriginalRole))
+		}
+		c.Set("auth_method", authMethod)
+		c.Set("user", user)
+
+		if actor := user.ImpersonatedBy; actor != nil {
+			c.Set("impersonating", true)
+			c.Set("actor_id", actor.ID.String())
+			c.Set("actor_username", actor.Username)
+			c.Set("actor_role", string(actor.Role))
+
+			// Audit every request made on behalf of another user
+			a.logger.Info("Impersonated request",
+				zap.String("actor_id", actor.ID.String()),
+				zap.String("actor_username", actor.Username),
+				zap.String("actor_role", string(actor.Role)),
+				zap.String("user_id", user.ID.String()),
+				zap.String("username", user.Username),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("client_ip", c.ClientIP()),
+			)
+		}
 
 		c.Next()
 	}
@@ -80,7 +204,7 @@ func (a *AuthMiddleware) RequireRole(requiredRole models.Role) gin.HandlerFunc {
 
 		if !userModel.HasRole(requiredRole) {
 			a.logger.Warn("Insufficient permissions",
-				zap.String("user_id", userModel.ID),
+				zap.String("user_id", userModel.ID.String()),
 				zap.String("user_role", string(userModel.Role)),
 				zap.String("required_role", string(requiredRole)),
 			)
@@ -111,7 +235,7 @@ func (a *AuthMiddleware) RequireAnyRole(roles []models.Role) gin.HandlerFunc {
 
 		if !userModel.HasAnyRole(roles) {
 			a.logger.Warn("Insufficient permissions",
-				zap.String("user_id", userModel.ID),
+				zap.String("user_id", userModel.ID.String()),
 				zap.String("user_role", string(userModel.Role)),
 			)
 			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
@@ -123,6 +247,119 @@ func (a *AuthMiddleware) RequireAnyRole(roles []models.Role) gin.HandlerFunc {
 	}
 }
 
+// RequirePermission checks the policy for the user's role and user ID.
+// Path parameters in the resource, such as "projects/:id", are replaced by
+// their values so that policies can target individual records. The action
+// is expanded the same way, e.g. "transition/:transition".
+func (a *AuthMiddleware) RequirePermission(resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authorize(c, resource, action, "")
+	}
+}
+
+// RequireProjectPermission is RequirePermission for routes that target a
+// single project. In addition to the global role, the caller's role on the
+// project named by the path parameter is checked as "project:<role>".
+func (a *AuthMiddleware) RequireProjectPermission(projectParam, resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authorize(c, resource, action, projectParam)
+	}
+}
+
+func (a *AuthMiddleware) authorize(c *gin.Context, resource, action, projectParam string) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Abort()
+		return
+	}
+
+	userModel, ok := user.(*models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context"})
+		c.Abort()
+		return
+	}
+
+	if a.enforcer == nil {
+		a.logger.Error("Permission check without an enforcer", zap.String("resource", resource))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+		return
+	}
+
+	subjects := []string{string(userModel.Role), userModel.ID.String()}
+	if projectParam != "" {
+		projectRole, err := a.projectRole(c, projectParam, userModel.ID)
+		if err != nil {
+			a.logger.Error("Failed to resolve project role", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Permission check failed"})
+			c.Abort()
+			return
+		}
+		if projectRole != "" {
+			subjects = append(subjects, "project:"+projectRole)
+			c.Set("project_role", projectRole)
+		}
+	}
+
+	object := expandResource(c, resource)
+	action = expandResource(c, action)
+	allowed := false
+	for _, subject := range subjects {
+		ok, err := a.enforcer.Enforce(subject, object, action)
+		if err != nil {
+			a.logger.Error("Permission check failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Permission check failed"})
+			c.Abort()
+			return
+		}
+		if ok {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		a.logger.Warn("Insufficient permissions",
+			zap.String("user_id", userModel.ID.String()),
+			zap.String("user_role", string(userModel.Role)),
+			zap.String("resource", object),
+			zap.String("action", action),
+		)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// projectRole returns the user's role on the project named by the path
+// parameter, or "" when the user is not a member or the ID is malformed.
+func (a *AuthMiddleware) projectRole(c *gin.Context, projectParam string, userID uuid.UUID) (string, error) {
+	if a.projectRoles == nil {
+		return "", nil
+	}
+
+	projectID, err := uuid.Parse(c.Param(projectParam))
+	if err != nil {
+		return "", nil
+	}
+
+	return a.projectRoles.GetMemberRole(c.Request.Context(), projectID, userID)
+}
+
+func expandResource(c *gin.Context, resource string) string {
+	segments := strings.Split(resource, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = c.Param(segment[1:])
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
 func (a *AuthMiddleware) extractToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -138,6 +375,31 @@ func (a *AuthMiddleware) extractToken(c *gin.Context) string {
 	return parts[1]
 }
 
+func actorFromClaims(claims *Claims) (*models.Actor, error) {
+	actorID, err := uuid.Parse(claims.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	actorSessionID, err := uuid.Parse(claims.ActorSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Actor{
+		ID:        actorID,
+		Username:  claims.ActorUsername,
+		Role:      models.Role(claims.ActorRole),
+		SessionID: actorSessionID,
+	}, nil
+}
+
+// isJWT reports whether the token has the header.payload.signature shape of a
+// JWT. API tokens are opaque hex strings and never contain a dot.
+func isJWT(tokenString string) bool {
+	return strings.Count(tokenString, ".") == 2
+}
+
 func (a *AuthMiddleware) validateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -158,22 +420,6 @@ func (a *AuthMiddleware) validateToken(tokenString string) (*Claims, error) {
 		return claims, nil
 	}
 
-	return nil, jwt.ErrTokenInvalid
-}
-
-func (a *AuthMiddleware) GenerateToken(user *models.User, expiresIn time.Duration) (string, error) {
-	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     string(user.Role),
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(a.jwtSecret))
+	return nil, jwt.ErrTokenInvalidClaims
 }
 
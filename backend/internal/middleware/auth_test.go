@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const testSecret = "test-secret"
+
+// fakeValidator treats the sessions in active as live and the tokens in
+// apiTokens as valid, and records which sessions were checked
+type fakeValidator struct {
+	active    map[uuid.UUID]bool
+	apiTokens map[string]*models.User
+	checked   []uuid.UUID
+}
+
+func (v *fakeValidator) ValidateAPIToken(ctx context.Context, tokenString string) (*models.User, error) {
+	if user, ok := v.apiTokens[tokenString]; ok {
+		return user, nil
+	}
+	return nil, errors.New("invalid API token")
+}
+
+func (v *fakeValidator) ValidateSession(ctx context.Context, sessionID uuid.UUID) error {
+	v.checked = append(v.checked, sessionID)
+	if !v.active[sessionID] {
+		return errors.New("session revoked")
+	}
+	return nil
+}
+
+func signToken(t *testing.T, secret string, claims *Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func sessionClaims(userID, sessionID uuid.UUID, expiresAt time.Time) *Claims {
+	return &Claims{
+		UserID:   userID.String(),
+		Username: "alice",
+		Role:     string(models.RoleUser),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+// serve runs RequireAuth with the given Authorization header and returns
+// the status and the user the handler saw, if it was reached
+func serve(validator TokenValidator, authorization string) (int, *models.User) {
+	gin.SetMode(gin.TestMode)
+	auth := NewAuthMiddleware(testSecret, validator, nil, nil, zap.NewNop())
+
+	var seen *models.User
+	router := gin.New()
+	router.GET("/", auth.RequireAuth(), func(c *gin.Context) {
+		user, _ := c.Get("user")
+		seen = user.(*models.User)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(w, req)
+	return w.Code, seen
+}
+
+func TestRequireAuthSessions(t *testing.T) {
+	userID := uuid.New()
+	live := uuid.New()
+	revoked := uuid.New()
+	validator := &fakeValidator{active: map[uuid.UUID]bool{live: true}}
+	hour := time.Now().Add(time.Hour)
+
+	noSession := sessionClaims(userID, live, hour)
+	noSession.ID = ""
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"live session", "Bearer " + signToken(t, testSecret, sessionClaims(userID, live, hour)), http.StatusOK},
+		{"revoked session", "Bearer " + signToken(t, testSecret, sessionClaims(userID, revoked, hour)), http.StatusUnauthorized},
+		{"token without session ID", "Bearer " + signToken(t, testSecret, noSession), http.StatusUnauthorized},
+		{"wrong secret", "Bearer " + signToken(t, "other-secret", sessionClaims(userID, live, hour)), http.StatusUnauthorized},
+		{"expired", "Bearer " + signToken(t, testSecret, sessionClaims(userID, live, time.Now().Add(-time.Minute))), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user := serve(validator, tt.header)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if status == http.StatusOK && (user == nil || user.ID != userID) {
+				t.Errorf("user = %+v, want %s", user, userID)
+			}
+		})
+	}
+
+	// Each request with a well-formed session token checks the session
+	for _, id := range validator.checked {
+		if id != live && id != revoked {
+			t.Errorf("checked unexpected session %s", id)
+		}
+	}
+	if len(validator.checked) != 2 {
+		t.Errorf("checked %d sessions, want 2", len(validator.checked))
+	}
+}
+
+func TestRequireAuthSessionRevokedLater(t *testing.T) {
+	sessionID := uuid.New()
+	validator := &fakeValidator{active: map[uuid.UUID]bool{sessionID: true}}
+	header := "Bearer " + signToken(t, testSecret, sessionClaims(uuid.New(), sessionID, time.Now().Add(time.Hour)))
+
+	if status, _ := serve(validator, header); status != http.StatusOK {
+		t.Fatalf("before logout: status = %d", status)
+	}
+	delete(validator.active, sessionID)
+	if status, _ := serve(validator, header); status != http.StatusUnauthorized {
+		t.Errorf("after logout: status = %d, want 401", status)
+	}
+}
+
+func TestRequireAuthImpersonation(t *testing.T) {
+	sessionID := uuid.New()
+	actorID := uuid.New()
+	actorSessionID := uuid.New()
+	validator := &fakeValidator{active: map[uuid.UUID]bool{sessionID: true}}
+
+	claims := sessionClaims(uuid.New(), sessionID, time.Now().Add(time.Hour))
+	claims.ActorID = actorID.String()
+	claims.ActorUsername = "admin"
+	claims.ActorRole = string(models.RoleSysadmin)
+	claims.ActorSessionID = actorSessionID.String()
+
+	status, user := serve(validator, "Bearer "+signToken(t, testSecret, claims))
+	if status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	actor := user.ImpersonatedBy
+	if actor == nil || actor.ID != actorID || actor.SessionID != actorSessionID || actor.Role != models.RoleSysadmin {
+		t.Errorf("ImpersonatedBy = %+v", actor)
+	}
+
+	claims.ActorSessionID = "not-a-uuid"
+	if status, _ := serve(validator, "Bearer "+signToken(t, testSecret, claims)); status != http.StatusUnauthorized {
+		t.Errorf("malformed actor: status = %d, want 401", status)
+	}
+}
+
+func TestRequireAuthAPITokens(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), Username: "bot", Role: models.RoleUser}
+	validator := &fakeValidator{apiTokens: map[string]*models.User{"abc123": owner}}
+
+	if status, user := serve(validator, "Bearer abc123"); status != http.StatusOK || user != owner {
+		t.Errorf("valid API token: status = %d, user = %+v", status, user)
+	}
+	if status, _ := serve(validator, "Bearer nope"); status != http.StatusUnauthorized {
+		t.Errorf("unknown API token: status = %d, want 401", status)
+	}
+	if status, _ := serve(nil, "Bearer abc123"); status != http.StatusUnauthorized {
+		t.Errorf("without validator: status = %d, want 401", status)
+	}
+}
+
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file uploaded to a project. DownloadURL is a signed link
+// that works without authentication until DownloadExpiresAt.
+type Attachment struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	ProjectID         uuid.UUID  `json:"project_id" db:"project_id"`
+	Filename          string     `json:"filename" db:"filename"`
+	ContentType       string     `json:"content_type" db:"content_type"`
+	Size              int64      `json:"size" db:"size_bytes"`
+	Checksum          string     `json:"checksum_sha256" db:"checksum_sha256"`
+	StorageKey        string     `json:"-" db:"storage_key"`
+	UploadedBy        *uuid.UUID `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	DownloadURL       string     `json:"download_url,omitempty" db:"-"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty" db:"-"`
+}
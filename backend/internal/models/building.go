@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApartmentBuilding is a building of units in a Colony or HousingEstate project
+type ApartmentBuilding struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ProjectID uuid.UUID `json:"project_id" db:"project_id"`
+	Name      string    `json:"name" db:"name"`
+	Address   *string   `json:"address,omitempty" db:"address"`
+	Floors    *int      `json:"floors,omitempty" db:"floors"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateBuildingRequest struct {
+	Name    string  `json:"name" validate:"required,notblank,max=255"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+	Floors  *int    `json:"floors,omitempty" validate:"omitempty,min=1,max=300"`
+}
+
+type UpdateBuildingRequest struct {
+	Name    *string `json:"name,omitempty" validate:"omitempty,notblank,max=255"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+	Floors  *int    `json:"floors,omitempty" validate:"omitempty,min=1,max=300"`
+}
+
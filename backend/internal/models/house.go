@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// House is a standalone house of a project, or a unit of one of its
+// apartment buildings when BuildingID is set
+type House struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ProjectID    uuid.UUID  `json:"project_id" db:"project_id"`
+	BuildingID   *uuid.UUID `json:"building_id,omitempty" db:"building_id"`
+	UnitNumber   *string    `json:"unit_number,omitempty" db:"unit_number"`
+	Name         string     `json:"name" db:"name"`
+	CategoryType *string    `json:"category_type,omitempty" db:"category_type"`
+	SizeSqm      *float64   `json:"size_sqm,omitempty" db:"size_sqm"`
+	Metadata     JSONB      `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsUnit reports whether the house is a unit of an apartment building
+func (h *House) IsUnit() bool {
+	return h.BuildingID != nil
+}
+
+// HouseDetail is a house with its owners and, for units, its building
+type HouseDetail struct {
+	House
+	Owners   []*HouseOwner      `json:"owners"`
+	Building *ApartmentBuilding `json:"building,omitempty"`
+}
+
+// CreateHouseRequest creates a standalone house, or a unit when BuildingID
+// is set. Units need a unit number, category and size.
+type CreateHouseRequest struct {
+	BuildingID   *uuid.UUID             `json:"building_id,omitempty"`
+	UnitNumber   *string                `json:"unit_number,omitempty" validate:"omitempty,notblank,max=50"`
+	Name         string                 `json:"name" validate:"required,notblank,max=255"`
+	CategoryType *string                `json:"category_type,omitempty" validate:"omitempty,notblank,max=50"`
+	SizeSqm      *float64               `json:"size_sqm,omitempty" validate:"omitempty,gt=0"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	// Owners optionally assigns the initial owners; percentages must add up to 100
+	Owners []OwnerShareInput `json:"owners,omitempty" validate:"omitempty,dive"`
+}
+
+type UpdateHouseRequest struct {
+	UnitNumber   *string                `json:"unit_number,omitempty" validate:"omitempty,notblank,max=50"`
+	Name         *string                `json:"name,omitempty" validate:"omitempty,notblank,max=255"`
+	CategoryType *string                `json:"category_type,omitempty" validate:"omitempty,notblank,max=50"`
+	SizeSqm      *float64               `json:"size_sqm,omitempty" validate:"omitempty,gt=0"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// HouseOwner is an owner's share of a house
+type HouseOwner struct {
+	HouseID    uuid.UUID `json:"house_id" db:"house_id"`
+	OwnerID    uuid.UUID `json:"owner_id" db:"owner_id"`
+	Percentage float64   `json:"percentage" db:"percentage"`
+	Role       OwnerRole `json:"role" db:"role"`
+	Owner      *Owner    `json:"owner,omitempty"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetHouseOwnersRequest replaces all owners of a house at once. The
+// percentages must add up to 100.
+type SetHouseOwnersRequest struct {
+	Owners []OwnerShareInput `json:"owners" validate:"required,min=1,dive"`
+}
+
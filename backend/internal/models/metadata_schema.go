@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MetadataSchema is the JSON Schema that the metadata of projects of one
+// type must satisfy. Fields lists the top-level properties that ListProjects
+// can filter on.
+type MetadataSchema struct {
+	ProjectType ProjectType     `json:"project_type" db:"project_type"`
+	Schema      json.RawMessage `json:"schema" swaggertype:"object" db:"schema"`
+	Fields      []MetadataField `json:"fields"`
+	UpdatedBy   *uuid.UUID      `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// MetadataField is a scalar metadata property defined by a schema. Range
+// reports whether metadata_min and metadata_max filters apply to it.
+type MetadataField struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+	Range  bool   `json:"range"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrgRole is a user's role within an organization
+type OrgRole string
+
+const (
+	OrgRoleOwner   OrgRole = "owner"
+	OrgRoleAdmin   OrgRole = "admin"
+	OrgRoleManager OrgRole = "manager"
+	OrgRoleMember  OrgRole = "member"
+	OrgRoleViewer  OrgRole = "viewer"
+)
+
+// IsValid reports whether the organization role is known
+func (r OrgRole) IsValid() bool {
+	switch r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleManager, OrgRoleMember, OrgRoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanManage reports whether the role may manage the organization, its
+// members and its invitations
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+// CanWrite reports whether the role may create and modify the
+// organization's projects
+func (r OrgRole) CanWrite() bool {
+	return r.IsValid() && r != OrgRoleViewer
+}
+
+type Organization struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Type        string    `json:"type" db:"type"`
+	Address     *string   `json:"address,omitempty" db:"address"`
+	City        *string   `json:"city,omitempty" db:"city"`
+	State       *string   `json:"state,omitempty" db:"state"`
+	PostalCode  *string   `json:"postal_code,omitempty" db:"postal_code"`
+	Country     *string   `json:"country,omitempty" db:"country"`
+	Phone       *string   `json:"phone,omitempty" db:"phone"`
+	Email       *string   `json:"email,omitempty" db:"email"`
+	Website     *string   `json:"website,omitempty" db:"website"`
+	Metadata    JSONB     `json:"metadata,omitempty" db:"metadata"`
+	Role        *OrgRole  `json:"role,omitempty" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateOrganizationRequest struct {
+	Name        string                 `json:"name" validate:"required,notblank,max=255"`
+	Description *string                `json:"description,omitempty"`
+	Type        string                 `json:"type" validate:"required,oneof=Company Government NGO Individual Partnership"`
+	Address     *string                `json:"address,omitempty" validate:"omitempty,max=255"`
+	City        *string                `json:"city,omitempty" validate:"omitempty,max=100"`
+	State       *string                `json:"state,omitempty" validate:"omitempty,max=100"`
+	PostalCode  *string                `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Country     *string                `json:"country,omitempty" validate:"omitempty,max=100"`
+	Phone       *string                `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Email       *string                `json:"email,omitempty" validate:"omitempty,email"`
+	Website     *string                `json:"website,omitempty" validate:"omitempty,url"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name        *string                `json:"name,omitempty" validate:"omitempty,notblank,max=255"`
+	Description *string                `json:"description,omitempty"`
+	Type        *string                `json:"type,omitempty" validate:"omitempty,oneof=Company Government NGO Individual Partnership"`
+	Address     *string                `json:"address,omitempty" validate:"omitempty,max=255"`
+	City        *string                `json:"city,omitempty" validate:"omitempty,max=100"`
+	State       *string                `json:"state,omitempty" validate:"omitempty,max=100"`
+	PostalCode  *string                `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Country     *string                `json:"country,omitempty" validate:"omitempty,max=100"`
+	Phone       *string                `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Email       *string                `json:"email,omitempty" validate:"omitempty,email"`
+	Website     *string                `json:"website,omitempty" validate:"omitempty,url"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type OrganizationMember struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	OrganizationID uuid.UUID `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Username       string    `json:"username" db:"username"`
+	Email          string    `json:"email" db:"email"`
+	Role           OrgRole   `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role OrgRole `json:"role" validate:"required,oneof=owner admin manager member viewer"`
+}
+
+type OrganizationInvitation struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	Email          string     `json:"email" db:"email"`
+	Role           OrgRole    `json:"role" db:"role"`
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	Token          string     `json:"token,omitempty" db:"-"` // Only returned on creation
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateInvitationRequest struct {
+	Email string  `json:"email" validate:"required,email"`
+	Role  OrgRole `json:"role,omitempty" validate:"omitempty,oneof=admin manager member viewer"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
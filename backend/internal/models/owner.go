@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OwnerRole is an owner's role in a project or house
+type OwnerRole string
+
+const (
+	OwnerRolePrimary OwnerRole = "primary"
+	OwnerRoleCoOwner OwnerRole = "co-owner"
+)
+
+const (
+	// MaxProjectOwners is the most owners a project may have
+	MaxProjectOwners = 10
+	// OwnershipTotal is what the percentages of an ownership must add up to
+	OwnershipTotal = 100
+)
+
+type Owner struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     *string   `json:"email,omitempty" db:"email"`
+	Phone     *string   `json:"phone,omitempty" db:"phone"`
+	Metadata  JSONB     `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateOwnerRequest struct {
+	Name     string                 `json:"name" validate:"required,notblank,max=255"`
+	Email    *string                `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string                `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type UpdateOwnerRequest struct {
+	Name     *string                `json:"name,omitempty" validate:"omitempty,notblank,max=255"`
+	Email    *string                `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string                `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ProjectOwner is an owner's share of a project
+type ProjectOwner struct {
+	ProjectID  uuid.UUID `json:"project_id" db:"project_id"`
+	OwnerID    uuid.UUID `json:"owner_id" db:"owner_id"`
+	Percentage float64   `json:"percentage" db:"percentage"`
+	Role       OwnerRole `json:"role" db:"role"`
+	Owner      *Owner    `json:"owner,omitempty"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OwnerShareInput assigns a share of a project or house to an owner
+type OwnerShareInput struct {
+	OwnerID    uuid.UUID `json:"owner_id" validate:"required"`
+	Percentage float64   `json:"percentage" validate:"gt=0,lte=100"`
+	Role       OwnerRole `json:"role,omitempty" validate:"omitempty,oneof=primary co-owner"`
+}
+
+// SetProjectOwnersRequest replaces all owners of a project at once. The
+// percentages must add up to 100.
+type SetProjectOwnersRequest struct {
+	Owners []OwnerShareInput `json:"owners" validate:"required,min=1,max=10,dive"`
+}
+
@@ -9,44 +9,102 @@ import (
 )
 
 type Project struct {
-	ID         uuid.UUID  `json:"id" db:"id"`
-	Name       string     `json:"name" db:"name"`
-	Address    *string    `json:"address,omitempty" db:"address"`
-	City       *string    `json:"city,omitempty" db:"city"`
-	State      *string    `json:"state,omitempty" db:"state"`
-	PostalCode *string    `json:"postal_code,omitempty" db:"postal_code"`
-	OwnerName  *string    `json:"owner_name,omitempty" db:"owner_name"`
-	Status     *string    `json:"status,omitempty" db:"status"`
-	Budget     *float64   `json:"budget,omitempty" db:"budget"`
-	StartDate  *time.Time `json:"start_date,omitempty" db:"start_date"`
-	EndDate    *time.Time `json:"end_date,omitempty" db:"end_date"`
-	Metadata   JSONB      `json:"metadata,omitempty" db:"metadata"`
-	Documents  JSONB      `json:"documents,omitempty" db:"documents"`
-	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	ID             uuid.UUID    `json:"id" db:"id"`
+	OrganizationID *uuid.UUID   `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string       `json:"name" db:"name"`
+	Address        *string      `json:"address,omitempty" db:"address"`
+	City           *string      `json:"city,omitempty" db:"city"`
+	State          *string      `json:"state,omitempty" db:"state"`
+	PostalCode     *string      `json:"postal_code,omitempty" db:"postal_code"`
+	Type           *ProjectType `json:"type,omitempty" db:"type"`
+	Latitude       *float64     `json:"latitude,omitempty" db:"latitude"`
+	Longitude      *float64     `json:"longitude,omitempty" db:"longitude"`
+	OwnerName      *string      `json:"owner_name,omitempty" db:"owner_name"`
+	Status         *string      `json:"status,omitempty" db:"status"`
+	Budget         *float64     `json:"budget,omitempty" db:"budget"`
+	StartDate      *time.Time   `json:"start_date,omitempty" db:"start_date"`
+	EndDate        *time.Time   `json:"end_date,omitempty" db:"end_date"`
+	Metadata       JSONB        `json:"metadata,omitempty" db:"metadata"`
+	Documents      JSONB        `json:"documents,omitempty" db:"documents"`
+	Version        int          `json:"version" db:"version"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID   `json:"updated_by,omitempty" db:"updated_by"`
+	DeletedAt      *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy      *uuid.UUID   `json:"deleted_by,omitempty" db:"deleted_by"`
+}
+
+// ProjectStatuses are the values allowed in projects.status
+var ProjectStatuses = []string{"planning", "active", "completed", "on-hold", "cancelled"}
+
+func IsValidProjectStatus(status string) bool {
+	for _, s := range ProjectStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectType is the kind of development a project is
+type ProjectType string
+
+const (
+	ProjectTypeColony           ProjectType = "Colony"
+	ProjectTypeIndependentHouse ProjectType = "IndependentHouse"
+	ProjectTypeLinkHouse        ProjectType = "LinkHouse"
+	ProjectTypeHousingEstate    ProjectType = "HousingEstate"
+)
+
+// ProjectTypes are the values allowed in projects.type
+var ProjectTypes = []ProjectType{ProjectTypeColony, ProjectTypeIndependentHouse, ProjectTypeLinkHouse, ProjectTypeHousingEstate}
+
+// IsValid reports whether the project type is known
+func (t ProjectType) IsValid() bool {
+	for _, known := range ProjectTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsBuildings reports whether projects of this type may contain
+// apartment buildings
+func (t ProjectType) AllowsBuildings() bool {
+	return t == ProjectTypeColony || t == ProjectTypeHousingEstate
 }
 
 type CreateProjectRequest struct {
-	Name       string                 `json:"name" validate:"required,min=1,max=255"`
-	Address    *string                `json:"address,omitempty" validate:"omitempty,max=255"`
-	City       *string                `json:"city,omitempty" validate:"omitempty,max=100"`
-	State      *string                `json:"state,omitempty" validate:"omitempty,max=100"`
-	PostalCode *string                `json:"postal_code,omitempty" validate:"omitempty,max=20"`
-	OwnerName  *string                `json:"owner_name,omitempty" validate:"omitempty,max=255"`
-	Status     *string                `json:"status,omitempty" validate:"omitempty,oneof=planning active completed on-hold cancelled"`
-	Budget     *float64               `json:"budget,omitempty" validate:"omitempty,min=0"`
-	StartDate  *time.Time             `json:"start_date,omitempty"`
-	EndDate    *time.Time             `json:"end_date,omitempty"`
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
-	Documents  map[string]interface{} `json:"documents,omitempty"`
+	OrganizationID *uuid.UUID             `json:"organization_id,omitempty"`
+	Name           string                 `json:"name" validate:"required,notblank,max=255"`
+	Address        *string                `json:"address,omitempty" validate:"omitempty,max=255"`
+	City           *string                `json:"city,omitempty" validate:"omitempty,max=100"`
+	State          *string                `json:"state,omitempty" validate:"omitempty,max=100"`
+	PostalCode     *string                `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Type           *ProjectType           `json:"type,omitempty" validate:"omitempty,oneof=Colony IndependentHouse LinkHouse HousingEstate"`
+	Latitude       *float64               `json:"latitude,omitempty" validate:"omitempty,gte=-90,lte=90"`
+	Longitude      *float64               `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
+	OwnerName      *string                `json:"owner_name,omitempty" validate:"omitempty,max=255"`
+	Status         *string                `json:"status,omitempty" validate:"omitempty,oneof=planning active completed on-hold cancelled"`
+	Budget         *float64               `json:"budget,omitempty" validate:"omitempty,min=0"`
+	StartDate      *time.Time             `json:"start_date,omitempty"`
+	EndDate        *time.Time             `json:"end_date,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Documents      map[string]interface{} `json:"documents,omitempty"`
+	// Owners optionally assigns the initial owners; percentages must add up to 100
+	Owners []OwnerShareInput `json:"owners,omitempty" validate:"omitempty,max=10,dive"`
 }
 
 type UpdateProjectRequest struct {
-	Name       *string                `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Name       *string                `json:"name,omitempty" validate:"omitempty,notblank,max=255"`
 	Address    *string                `json:"address,omitempty" validate:"omitempty,max=255"`
 	City       *string                `json:"city,omitempty" validate:"omitempty,max=100"`
 	State      *string                `json:"state,omitempty" validate:"omitempty,max=100"`
 	PostalCode *string                `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Type       *ProjectType           `json:"type,omitempty" validate:"omitempty,oneof=Colony IndependentHouse LinkHouse HousingEstate"`
+	Latitude   *float64               `json:"latitude,omitempty" validate:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64               `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
 	OwnerName  *string                `json:"owner_name,omitempty" validate:"omitempty,max=255"`
 	Status     *string                `json:"status,omitempty" validate:"omitempty,oneof=planning active completed on-hold cancelled"`
 	Budget     *float64               `json:"budget,omitempty" validate:"omitempty,min=0"`
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectAnalytics aggregates the projects matching a ListProjects filter
+type ProjectAnalytics struct {
+	TotalProjects int64            `json:"total_projects"`
+	TotalBudget   float64          `json:"total_budget"`
+	ByStatus      []AnalyticsGroup `json:"by_status"`
+	ByCity        []AnalyticsGroup `json:"by_city"`
+	ByState       []AnalyticsGroup `json:"by_state"`
+	// ByStartMonth is keyed by "YYYY-MM" of the start date
+	ByStartMonth []AnalyticsGroup `json:"by_start_month"`
+	// AverageDurationDays covers projects with both a start and an end date
+	AverageDurationDays *float64       `json:"average_duration_days"`
+	Overdue             OverdueSummary `json:"overdue"`
+	BudgetBuckets       []BudgetBucket `json:"budget_buckets"`
+}
+
+// AnalyticsGroup counts projects sharing a key. A nil key groups projects
+// where the value is not set.
+type AnalyticsGroup struct {
+	Key         *string `json:"key"`
+	Count       int64   `json:"count"`
+	TotalBudget float64 `json:"total_budget"`
+}
+
+// OverdueSummary covers projects whose end date has passed while they are
+// still open. Projects lists the most overdue ones first.
+type OverdueSummary struct {
+	Count       int64            `json:"count"`
+	TotalBudget float64          `json:"total_budget"`
+	Projects    []OverdueProject `json:"projects"`
+}
+
+type OverdueProject struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Status      *string   `json:"status,omitempty"`
+	EndDate     time.Time `json:"end_date"`
+	DaysOverdue int       `json:"days_overdue"`
+}
+
+// BudgetBucket counts projects with Min <= budget < Max. A nil bound is
+// open-ended; both nil counts projects without a budget.
+type BudgetBucket struct {
+	Min         *float64 `json:"min"`
+	Max         *float64 `json:"max"`
+	Count       int64    `json:"count"`
+	TotalBudget float64  `json:"total_budget"`
+}
+
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectRole is a user's role within a single project, independent of
+// their global Role.
+type ProjectRole string
+
+const (
+	ProjectRoleAdmin    ProjectRole = "admin"
+	ProjectRoleSubAdmin ProjectRole = "subadmin"
+	ProjectRoleUser     ProjectRole = "user"
+)
+
+// projectRoleLimits is the maximum number of members per role in a project
+var projectRoleLimits = map[ProjectRole]int{
+	ProjectRoleAdmin:    4,
+	ProjectRoleSubAdmin: 10,
+	ProjectRoleUser:     100,
+}
+
+// IsValid reports whether the project role is known
+func (r ProjectRole) IsValid() bool {
+	_, ok := projectRoleLimits[r]
+	return ok
+}
+
+// Limit returns the maximum number of members a project may have with this role
+func (r ProjectRole) Limit() int {
+	return projectRoleLimits[r]
+}
+
+// CanModifyProject reports whether members with this role may modify the
+// project itself
+func (r ProjectRole) CanModifyProject() bool {
+	return r == ProjectRoleAdmin || r == ProjectRoleSubAdmin
+}
+
+type ProjectMember struct {
+	ID        uuid.UUID   `json:"id" db:"id"`
+	ProjectID uuid.UUID   `json:"project_id" db:"project_id"`
+	UserID    uuid.UUID   `json:"user_id" db:"user_id"`
+	Username  string      `json:"username" db:"username"`
+	Email     string      `json:"email" db:"email"`
+	Role      ProjectRole `json:"role" db:"role"`
+	AddedBy   *uuid.UUID  `json:"added_by,omitempty" db:"added_by"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+type AddProjectMemberRequest struct {
+	Username string      `json:"username" validate:"required"`
+	Role     ProjectRole `json:"role" validate:"required,oneof=admin subadmin user"`
+}
+
+type UpdateProjectMemberRequest struct {
+	Role ProjectRole `json:"role" validate:"required,oneof=admin subadmin user"`
+}
+
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectStatusChange is one entry of a project's status history. Entries
+// without a transition record the status a project was created with.
+type ProjectStatusChange struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	ProjectID  uuid.UUID  `json:"project_id" db:"project_id"`
+	FromStatus *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Transition *string    `json:"transition,omitempty" db:"transition"`
+	Reason     *string    `json:"reason,omitempty" db:"reason"`
+	ChangedBy  *uuid.UUID `json:"changed_by,omitempty" db:"changed_by"`
+	ChangedAt  time.Time  `json:"changed_at" db:"changed_at"`
+	// LeftAt is when the project moved on to its next status, nil while it
+	// is still in this one. DurationSeconds runs until LeftAt or now.
+	LeftAt          *time.Time `json:"left_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+}
+
+type TransitionProjectRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000"`
+}
+
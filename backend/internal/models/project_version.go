@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectVersion is a snapshot of a project as it was at a given version.
+// Archived versions come from project_versions; the current version is
+// built from the project row itself.
+type ProjectVersion struct {
+	ProjectID      uuid.UUID    `json:"project_id" db:"project_id"`
+	Version        int          `json:"version" db:"version"`
+	OrganizationID *uuid.UUID   `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string       `json:"name" db:"name"`
+	Address        *string      `json:"address,omitempty" db:"address"`
+	City           *string      `json:"city,omitempty" db:"city"`
+	State          *string      `json:"state,omitempty" db:"state"`
+	PostalCode     *string      `json:"postal_code,omitempty" db:"postal_code"`
+	Type           *ProjectType `json:"type,omitempty" db:"type"`
+	Latitude       *float64     `json:"latitude,omitempty" db:"latitude"`
+	Longitude      *float64     `json:"longitude,omitempty" db:"longitude"`
+	OwnerName      *string      `json:"owner_name,omitempty" db:"owner_name"`
+	Status         *string      `json:"status,omitempty" db:"status"`
+	Budget         *float64     `json:"budget,omitempty" db:"budget"`
+	StartDate      *time.Time   `json:"start_date,omitempty" db:"start_date"`
+	EndDate        *time.Time   `json:"end_date,omitempty" db:"end_date"`
+	Metadata       JSONB        `json:"metadata,omitempty" db:"metadata"`
+	Documents      JSONB        `json:"documents,omitempty" db:"documents"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID   `json:"updated_by,omitempty" db:"updated_by"`
+	ArchivedAt     *time.Time   `json:"archived_at,omitempty" db:"archived_at"`
+	ArchivedBy     *uuid.UUID   `json:"archived_by,omitempty" db:"archived_by"`
+	Current        bool         `json:"current"`
+}
+
+// VersionFieldChange is a single field that differs between two versions
+type VersionFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type ProjectVersionDiff struct {
+	ProjectID   uuid.UUID            `json:"project_id"`
+	FromVersion int                  `json:"from_version"`
+	ToVersion   int                  `json:"to_version"`
+	Changes     []VersionFieldChange `json:"changes"`
+}
+
+// VersionFromProject builds the version entry for the project's current state
+func VersionFromProject(p *Project) *ProjectVersion {
+	return &ProjectVersion{
+		ProjectID:      p.ID,
+		Version:        p.Version,
+		OrganizationID: p.OrganizationID,
+		Name:           p.Name,
+		Address:        p.Address,
+		City:           p.City,
+		State:          p.State,
+		PostalCode:     p.PostalCode,
+		Type:           p.Type,
+		Latitude:       p.Latitude,
+		Longitude:      p.Longitude,
+		OwnerName:      p.OwnerName,
+		Status:         p.Status,
+		Budget:         p.Budget,
+		StartDate:      p.StartDate,
+		EndDate:        p.EndDate,
+		Metadata:       p.Metadata,
+		Documents:      p.Documents,
+		UpdatedAt:      p.UpdatedAt,
+		UpdatedBy:      p.UpdatedBy,
+		Current:        true,
+	}
+}
+
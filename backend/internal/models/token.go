@@ -16,6 +16,20 @@ type APIToken struct {
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 }
 
+// RefreshToken is a single link in a rotating refresh-token family.
+// Only the SHA-256 hash of the token is persisted.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id" db:"family_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty" db:"replaced_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
 type CreateTokenRequest struct {
 	Name           string `json:"name" validate:"required,min=1,max=100"`
 	ExpiresInHours int    `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=24"`
@@ -41,3 +55,8 @@ func (t *APIToken) UpdateLastUsed() {
 	t.LastUsedAt = &now
 }
 
+// IsExpired checks if the refresh token has expired
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
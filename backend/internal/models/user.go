@@ -24,6 +24,22 @@ type User struct {
 	Role      Role      `json:"role" db:"role"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// OriginalRole is set while the user has switched down to a lower role;
+	// Role then holds the effective (inherited) role.
+	OriginalRole  *Role      `json:"original_role,omitempty" db:"-"`
+	RoleExpiresAt *time.Time `json:"role_expires_at,omitempty" db:"-"`
+
+	// ImpersonatedBy is set when an administrator is acting as this user.
+	ImpersonatedBy *Actor `json:"impersonated_by,omitempty" db:"-"`
+
+	// IsActive is only populated by the user management API
+	IsActive      *bool      `json:"is_active,omitempty" db:"is_active"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" db:"deactivated_at"`
+
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty" db:"suspended_until"`
+	SuspensionReason *string    `json:"suspension_reason,omitempty" db:"suspension_reason"`
+	FailedLoginCount int        `json:"-" db:"failed_login_count"`
 }
 
 type CreateUserRequest struct {
@@ -38,9 +54,15 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	User         *User  `json:"user"`
-	SessionToken string `json:"session_token"`
-	ExpiresAt    string `json:"expires_at"`
+	User                  *User  `json:"user"`
+	SessionToken          string `json:"session_token"`
+	ExpiresAt             string `json:"expires_at"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt string `json:"refresh_token_expires_at,omitempty"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 type UpdateUserRequest struct {
@@ -49,20 +71,122 @@ type UpdateUserRequest struct {
 	Role     *Role   `json:"role,omitempty"`
 }
 
-// HasRole checks if user has the required role or higher
-func (u *User) HasRole(required Role) bool {
-	roleHierarchy := map[Role]int{
-		RoleGuest:      1,
-		RoleUser:       2,
-		RoleLocaladmin: 3,
-		RoleSysadmin:   4,
-		RoleSuperuser:  5,
+// Actor is the administrator behind an impersonation session
+type Actor struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	SessionID uuid.UUID `json:"-"`
+}
+
+type ImpersonateRequest struct {
+	TargetUserID   *uuid.UUID `json:"target_user_id,omitempty"`
+	TargetUsername *string    `json:"target_username,omitempty"`
+}
+
+type ImpersonationStatusResponse struct {
+	Impersonating bool   `json:"impersonating"`
+	User          *User  `json:"user"`
+	Actor         *Actor `json:"actor,omitempty"`
+}
+
+type RoleInheritance struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	OriginalRole  Role       `json:"original_role" db:"original_role"`
+	InheritedRole Role       `json:"inherited_role" db:"inherited_role"`
+	InheritedAt   time.Time  `json:"inherited_at" db:"inherited_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+type SwitchRoleRequest struct {
+	TargetRole     Role `json:"target_role" validate:"required"`
+	ExpiresInHours int  `json:"expires_in_hours,omitempty" validate:"omitempty,min=1"`
+}
+
+type RoleStatusResponse struct {
+	OriginalRole   Role             `json:"original_role"`
+	EffectiveRole  Role             `json:"effective_role"`
+	IsInheriting   bool             `json:"is_inheriting"`
+	Inheritance    *RoleInheritance `json:"inheritance"`
+	AvailableRoles []Role           `json:"available_roles"`
+}
+
+type RoleSwitchResponse struct {
+	Message      string           `json:"message"`
+	Inheritance  *RoleInheritance `json:"inheritance,omitempty"`
+	SessionToken string           `json:"session_token,omitempty"`
+	ExpiresAt    string           `json:"expires_at,omitempty"`
+}
+
+var roleHierarchy = map[Role]int{
+	RoleGuest:      1,
+	RoleUser:       2,
+	RoleLocaladmin: 3,
+	RoleSysadmin:   4,
+	RoleSuperuser:  5,
+}
+
+// Level returns the position of the role in the hierarchy, or 0 for an
+// unknown role
+func (r Role) Level() int {
+	return roleHierarchy[r]
+}
+
+// IsValid checks if the role is one of the known roles
+func (r Role) IsValid() bool {
+	return r.Level() > 0
+}
+
+// CanInheritRole checks if a user with the original role may switch to the
+// target role. Only downgrades are allowed.
+func CanInheritRole(original, target Role) bool {
+	return target.IsValid() && target.Level() < original.Level()
+}
+
+// InheritableRoles returns the roles a user with the given role may switch to,
+// highest first
+func InheritableRoles(original Role) []Role {
+	roles := []Role{}
+	for _, role := range []Role{RoleSuperuser, RoleSysadmin, RoleLocaladmin, RoleUser, RoleGuest} {
+		if CanInheritRole(original, role) {
+			roles = append(roles, role)
+		}
 	}
+	return roles
+}
+
+// IsSuspended checks if the account is currently suspended
+func (u *User) IsSuspended() bool {
+	return u.SuspendedUntil != nil && time.Now().Before(*u.SuspendedUntil)
+}
+
+// CanManageRole checks if the user may modify accounts holding the given
+// role. Only superusers may manage their peers.
+func (u *User) CanManageRole(target Role) bool {
+	if u.Role == RoleSuperuser {
+		return true
+	}
+	return target.Level() < u.Role.Level()
+}
+
+// CanAssignRole checks if the user may grant the given role to someone else
+func (u *User) CanAssignRole(role Role) bool {
+	return role.IsValid() && u.CanManageRole(role)
+}
 
-	userLevel := roleHierarchy[u.Role]
-	requiredLevel := roleHierarchy[required]
+// HasRole checks if user has the required role or higher.
+// While a role switch is active this is evaluated against the inherited role.
+func (u *User) HasRole(required Role) bool {
+	return u.Role.Level() >= required.Level()
+}
 
-	return userLevel >= requiredLevel
+// CanAccessAllOrganizations reports whether the user sees data of every
+// organization. Everyone else is limited to their own organizations.
+func (u *User) CanAccessAllOrganizations() bool {
+	return u.HasRole(RoleSysadmin)
 }
 
 // HasAnyRole checks if user has any of the required roles
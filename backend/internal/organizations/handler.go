@@ -0,0 +1,470 @@
+package organizations
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List organizations
+// @Description Page through the organizations the current user belongs to, by name
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of organizations to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of organizations"
+// @Success 200 {object} pagination.Page[models.Organization]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /organizations [get]
+func (h *Handler) ListOrganizations(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgs, err := h.service.ListOrganizations(c.Request.Context(), user, params)
+	if err != nil {
+		h.respondError(c, "Failed to get organizations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// @Summary Create an organization
+// @Description Create an organization owned by the current user
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateOrganizationRequest true "Organization data"
+// @Success 201 {object} models.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /organizations [post]
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOrganizationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.service.CreateOrganization(c.Request.Context(), user, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create organization", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// @Summary Get an organization
+// @Description Get organization details by ID
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id} [get]
+func (h *Handler) GetOrganization(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	org, err := h.service.GetOrganization(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondError(c, "Failed to get organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// @Summary Update an organization
+// @Description Update organization details (organization owner or admin)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body models.UpdateOrganizationRequest true "Organization update data"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id} [put]
+func (h *Handler) UpdateOrganization(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOrganizationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.service.UpdateOrganization(c.Request.Context(), user, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// @Summary Delete an organization
+// @Description Delete an organization (organization owner only)
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id} [delete]
+func (h *Handler) DeleteOrganization(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOrganization(c.Request.Context(), user, id); err != nil {
+		h.respondError(c, "Failed to delete organization", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List organization members
+// @Description Page through the members of an organization and their roles, by username
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param limit query int false "Number of members to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of members"
+// @Success 200 {object} pagination.Page[models.OrganizationMember]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id}/members [get]
+func (h *Handler) ListMembers(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), user, id, params)
+	if err != nil {
+		h.respondError(c, "Failed to get organization members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Update an organization member
+// @Description Change a member's organization role (organization owner or admin)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "User ID"
+// @Param request body models.UpdateOrganizationMemberRequest true "New role"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/members/{userId} [put]
+func (h *Handler) UpdateMember(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := api.ParamID(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOrganizationMemberRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	if err := h.service.UpdateMemberRole(c.Request.Context(), user, orgID, userID, &req); err != nil {
+		h.respondError(c, "Failed to update organization member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// @Summary Remove an organization member
+// @Description Remove a user from an organization. Members may remove themselves.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/members/{userId} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := api.ParamID(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), user, orgID, userID); err != nil {
+		h.respondError(c, "Failed to remove organization member", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Invite a user to an organization
+// @Description Create an invitation for an email address (organization owner or admin). The token is only returned once.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body models.CreateInvitationRequest true "Invitation data"
+// @Success 201 {object} models.OrganizationInvitation
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/invitations [post]
+func (h *Handler) CreateInvitation(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	var req models.CreateInvitationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	invitation, err := h.service.CreateInvitation(c.Request.Context(), user, orgID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// @Summary List organization invitations
+// @Description Get pending invitations of an organization (organization owner or admin)
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.OrganizationInvitation
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id}/invitations [get]
+func (h *Handler) ListInvitations(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	invitations, err := h.service.ListInvitations(c.Request.Context(), user, orgID)
+	if err != nil {
+		h.respondError(c, "Failed to get invitations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// @Summary Revoke an invitation
+// @Description Revoke a pending organization invitation (organization owner or admin)
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param invitationId path string true "Invitation ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id}/invitations/{invitationId} [delete]
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := api.ParamID(c, "id", "Invalid organization ID")
+	if !ok {
+		return
+	}
+
+	invitationID, ok := api.ParamID(c, "invitationId", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeInvitation(c.Request.Context(), user, orgID, invitationID); err != nil {
+		h.respondError(c, "Failed to revoke invitation", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Accept an invitation
+// @Description Join an organization using an invitation token sent to the current user's email
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} models.OrganizationMember
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /invitations/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.AcceptInvitationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service.AcceptInvitation(c.Request.Context(), user, req.Token)
+	if err != nil {
+		h.respondError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrOrganizationNotFound, Status: http.StatusNotFound, Message: "Organization not found"},
+		api.ErrorResponse{Err: ErrMemberNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrInvitationNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrForbidden, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrInvalidRole, Status: http.StatusBadRequest},
+		api.ErrorResponse{Err: ErrAlreadyMember, Status: http.StatusConflict},
+		api.ErrorResponse{Err: ErrLastOwner, Status: http.StatusConflict},
+		api.ErrorResponse{Err: pagination.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+	)
+}
+
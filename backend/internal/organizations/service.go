@@ -0,0 +1,735 @@
+package organizations
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// invitationDuration is how long an invitation can be accepted
+const invitationDuration = 7 * 24 * time.Hour
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrMemberNotFound       = errors.New("organization member not found")
+	ErrInvitationNotFound   = errors.New("invitation not found or no longer valid")
+	ErrForbidden            = errors.New("insufficient organization permissions")
+	ErrAlreadyMember        = errors.New("user is already a member of this organization")
+	ErrLastOwner            = errors.New("organization must keep at least one owner")
+	ErrInvalidRole          = errors.New("invalid organization role")
+)
+
+type Service struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewService(database *db.Database, logger *zap.Logger) *Service {
+	return &Service{
+		db:     database,
+		logger: logger,
+	}
+}
+
+const organizationColumns = `o.id, o.name, o.description, o.type, o.address, o.city, o.state,
+		       o.postal_code, o.country, o.phone, o.email, o.website, o.metadata,
+		       o.created_at, o.updated_at`
+
+func scanOrganization(row pgx.Row, extra ...interface{}) (*models.Organization, error) {
+	var org models.Organization
+	dest := []interface{}{&org.ID, &org.Name, &org.Description, &org.Type, &org.Address, &org.City, &org.State,
+		&org.PostalCode, &org.Country, &org.Phone, &org.Email, &org.Website, &org.Metadata,
+		&org.CreatedAt, &org.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// memberRole returns the user's role in the organization. Non-members get
+// ErrOrganizationNotFound so that other tenants' organizations stay hidden.
+// Users who can access all organizations are treated as owners.
+func (s *Service) memberRole(ctx context.Context, user *models.User, orgID uuid.UUID) (models.OrgRole, error) {
+	var role *models.OrgRole
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT ou.role
+		FROM organizations o
+		LEFT JOIN organization_users ou
+		       ON ou.organization_id = o.id AND ou.user_id = $2 AND ou.deleted_at IS NULL
+		WHERE o.id = $1 AND o.deleted_at IS NULL`,
+		orgID, user.ID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrOrganizationNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization role: %w", err)
+	}
+
+	if user.CanAccessAllOrganizations() {
+		return models.OrgRoleOwner, nil
+	}
+	if role == nil {
+		return "", ErrOrganizationNotFound
+	}
+	return *role, nil
+}
+
+func (s *Service) requireManager(ctx context.Context, user *models.User, orgID uuid.UUID) (models.OrgRole, error) {
+	role, err := s.memberRole(ctx, user, orgID)
+	if err != nil {
+		return "", err
+	}
+	if !role.CanManage() {
+		return "", ErrForbidden
+	}
+	return role, nil
+}
+
+// ListOrganizations returns one page of the organizations the user belongs
+// to, or of every organization for users who can access all of them,
+// ordered by name. The list pages by offset.
+func (s *Service) ListOrganizations(ctx context.Context, user *models.User, params *pagination.Params) (*pagination.Page[*models.Organization], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	from := `
+		FROM organizations o
+		LEFT JOIN organization_users ou
+		       ON ou.organization_id = o.id AND ou.user_id = $1 AND ou.deleted_at IS NULL
+		WHERE o.deleted_at IS NULL`
+	if !user.CanAccessAllOrganizations() {
+		from += " AND ou.id IS NOT NULL"
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+organizationColumns+`, ou.role`+from+`
+		ORDER BY o.name, o.id
+		LIMIT $2 OFFSET $3`,
+		user.ID, params.Fetch(), params.Offset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var orgs []*models.Organization
+	for rows.Next() {
+		var role *models.OrgRole
+		org, err := scanOrganization(rows, &role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		org.Role = role
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	page := pagination.NewPage(orgs, params, pagination.OffsetCursor[*models.Organization](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*)"+from, user.ID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count organizations: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (s *Service) GetOrganization(ctx context.Context, user *models.User, id uuid.UUID) (*models.Organization, error) {
+	role, err := s.memberRole(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := scanOrganization(s.db.Pool.QueryRow(ctx, `
+		SELECT `+organizationColumns+`
+		FROM organizations o
+		WHERE o.id = $1 AND o.deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	org.Role = &role
+	return org, nil
+}
+
+// CreateOrganization creates the organization and makes the creator its owner
+func (s *Service) CreateOrganization(ctx context.Context, user *models.User, req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	now := time.Now()
+	org := &models.Organization{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		Address:     req.Address,
+		City:        req.City,
+		State:       req.State,
+		PostalCode:  req.PostalCode,
+		Country:     req.Country,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Website:     req.Website,
+		Metadata:    models.JSONB(req.Metadata),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organizations (
+			id, name, description, type, address, city, state, postal_code,
+			country, phone, email, website, metadata, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, 'US'), $10, $11, $12, $13, $14, $15
+		)`,
+		org.ID, org.Name, org.Description, org.Type, org.Address, org.City, org.State, org.PostalCode,
+		org.Country, org.Phone, org.Email, org.Website, org.Metadata, org.CreatedAt, org.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organization_users (organization_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)`,
+		org.ID, user.ID, models.OrgRoleOwner, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit organization: %w", err)
+	}
+
+	if org.Country == nil {
+		country := "US"
+		org.Country = &country
+	}
+	role := models.OrgRoleOwner
+	org.Role = &role
+
+	s.logger.Info("Organization created",
+		zap.String("organization_id", org.ID.String()),
+		zap.String("name", org.Name),
+		zap.String("created_by", user.ID.String()))
+
+	return org, nil
+}
+
+func (s *Service) UpdateOrganization(ctx context.Context, user *models.User, id uuid.UUID, req *models.UpdateOrganizationRequest) (*models.Organization, error) {
+	if _, err := s.requireManager(ctx, user, id); err != nil {
+		return nil, err
+	}
+
+	org, err := s.GetOrganization(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		org.Name = *req.Name
+	}
+	if req.Description != nil {
+		org.Description = req.Description
+	}
+	if req.Type != nil {
+		org.Type = *req.Type
+	}
+	if req.Address != nil {
+		org.Address = req.Address
+	}
+	if req.City != nil {
+		org.City = req.City
+	}
+	if req.State != nil {
+		org.State = req.State
+	}
+	if req.PostalCode != nil {
+		org.PostalCode = req.PostalCode
+	}
+	if req.Country != nil {
+		org.Country = req.Country
+	}
+	if req.Phone != nil {
+		org.Phone = req.Phone
+	}
+	if req.Email != nil {
+		org.Email = req.Email
+	}
+	if req.Website != nil {
+		org.Website = req.Website
+	}
+	if req.Metadata != nil {
+		org.Metadata = models.JSONB(req.Metadata)
+	}
+	org.UpdatedAt = time.Now()
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE organizations SET
+			name = $2, description = $3, type = $4, address = $5, city = $6, state = $7,
+			postal_code = $8, country = $9, phone = $10, email = $11, website = $12,
+			metadata = $13, updated_at = $14
+		WHERE id = $1 AND deleted_at IS NULL`,
+		org.ID, org.Name, org.Description, org.Type, org.Address, org.City, org.State,
+		org.PostalCode, org.Country, org.Phone, org.Email, org.Website,
+		org.Metadata, org.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	s.logger.Info("Organization updated",
+		zap.String("organization_id", org.ID.String()),
+		zap.String("updated_by", user.ID.String()))
+
+	return org, nil
+}
+
+// DeleteOrganization soft deletes the organization. Only owners may do this.
+func (s *Service) DeleteOrganization(ctx context.Context, user *models.User, id uuid.UUID) error {
+	role, err := s.memberRole(ctx, user, id)
+	if err != nil {
+		return err
+	}
+	if role != models.OrgRoleOwner {
+		return ErrForbidden
+	}
+
+	now := time.Now()
+	result, err := s.db.Pool.Exec(ctx,
+		"UPDATE organizations SET deleted_at = $2, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL",
+		id, now)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	s.logger.Info("Organization deleted",
+		zap.String("organization_id", id.String()),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
+// ListMembers returns one page of the members of an organization, ordered
+// by username. The list pages by offset.
+func (s *Service) ListMembers(ctx context.Context, user *models.User, orgID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.OrganizationMember], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+	if _, err := s.memberRole(ctx, user, orgID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT ou.id, ou.organization_id, ou.user_id, u.username, u.email, ou.role,
+		       ou.created_at, ou.updated_at
+		FROM organization_users ou
+		JOIN users u ON u.id = ou.user_id
+		WHERE ou.organization_id = $1 AND ou.deleted_at IS NULL
+		ORDER BY u.username, ou.id
+		LIMIT $2 OFFSET $3`, orgID, params.Fetch(), params.Offset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*models.OrganizationMember
+	for rows.Next() {
+		var member models.OrganizationMember
+		if err := rows.Scan(&member.ID, &member.OrganizationID, &member.UserID, &member.Username,
+			&member.Email, &member.Role, &member.CreatedAt, &member.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization member: %w", err)
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	page := pagination.NewPage(members, params, pagination.OffsetCursor[*models.OrganizationMember](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM organization_users WHERE organization_id = $1 AND deleted_at IS NULL",
+			orgID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count organization members: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// UpdateMemberRole changes a member's role. Only owners may grant or revoke
+// ownership, and the last owner cannot be demoted.
+func (s *Service) UpdateMemberRole(ctx context.Context, user *models.User, orgID, userID uuid.UUID, req *models.UpdateOrganizationMemberRequest) error {
+	if !req.Role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	actorRole, err := s.requireManager(ctx, user, orgID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockMember(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if (current == models.OrgRoleOwner || req.Role == models.OrgRoleOwner) && actorRole != models.OrgRoleOwner {
+		return ErrForbidden
+	}
+	if current == models.OrgRoleOwner && req.Role != models.OrgRoleOwner {
+		if err := ensureOtherOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE organization_users SET role = $3, updated_at = $4
+		WHERE organization_id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+		orgID, userID, req.Role, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit organization member: %w", err)
+	}
+
+	s.logger.Info("Organization member role changed",
+		zap.String("organization_id", orgID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("from_role", string(current)),
+		zap.String("to_role", string(req.Role)),
+		zap.String("updated_by", user.ID.String()))
+	return nil
+}
+
+// RemoveMember removes a user from the organization. Members may always
+// remove themselves; removing others requires owner or admin.
+func (s *Service) RemoveMember(ctx context.Context, user *models.User, orgID, userID uuid.UUID) error {
+	actorRole, err := s.memberRole(ctx, user, orgID)
+	if err != nil {
+		return err
+	}
+	if userID != user.ID && !actorRole.CanManage() {
+		return ErrForbidden
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockMember(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if current == models.OrgRoleOwner {
+		if userID != user.ID && actorRole != models.OrgRoleOwner {
+			return ErrForbidden
+		}
+		if err := ensureOtherOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE organization_users SET deleted_at = $3, updated_at = $3
+		WHERE organization_id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+		orgID, userID, now)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit organization member: %w", err)
+	}
+
+	s.logger.Info("Organization member removed",
+		zap.String("organization_id", orgID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("removed_by", user.ID.String()))
+	return nil
+}
+
+func lockMember(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID) (models.OrgRole, error) {
+	var role models.OrgRole
+	err := tx.QueryRow(ctx, `
+		SELECT role FROM organization_users
+		WHERE organization_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		FOR UPDATE`, orgID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrMemberNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization member: %w", err)
+	}
+	return role, nil
+}
+
+func ensureOtherOwner(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID) error {
+	var owners int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM organization_users
+		WHERE organization_id = $1 AND user_id <> $2 AND role = $3 AND deleted_at IS NULL`,
+		orgID, userID, models.OrgRoleOwner).Scan(&owners)
+	if err != nil {
+		return fmt.Errorf("failed to count organization owners: %w", err)
+	}
+	if owners == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// CreateInvitation invites an email address to join the organization. The
+// token is returned only once; it is stored hashed.
+func (s *Service) CreateInvitation(ctx context.Context, user *models.User, orgID uuid.UUID, req *models.CreateInvitationRequest) (*models.OrganizationInvitation, error) {
+	if req.Role == "" {
+		req.Role = models.OrgRoleMember
+	}
+	if !req.Role.IsValid() || req.Role == models.OrgRoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	if _, err := s.requireManager(ctx, user, orgID); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var isMember bool
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM organization_users ou
+			JOIN users u ON u.id = ou.user_id
+			WHERE ou.organization_id = $1 AND LOWER(u.email) = $2 AND ou.deleted_at IS NULL
+		)`, orgID, email).Scan(&isMember)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check organization membership: %w", err)
+	}
+	if isMember {
+		return nil, ErrAlreadyMember
+	}
+
+	token, tokenHash, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.OrganizationInvitation{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           req.Role,
+		InvitedBy:      &user.ID,
+		Token:          token,
+		ExpiresAt:      now.Add(invitationDuration),
+		CreatedAt:      now,
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `
+		INSERT INTO organization_invitations (id, organization_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		invitation.ID, invitation.OrganizationID, invitation.Email, invitation.Role, tokenHash,
+		invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	s.logger.Info("Organization invitation created",
+		zap.String("organization_id", orgID.String()),
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("invited_by", user.ID.String()))
+
+	return invitation, nil
+}
+
+// ListInvitations returns the organization's invitations that are still pending
+func (s *Service) ListInvitations(ctx context.Context, user *models.User, orgID uuid.UUID) ([]*models.OrganizationInvitation, error) {
+	if _, err := s.requireManager(ctx, user, orgID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT id, organization_id, email, role, invited_by, expires_at, accepted_at, revoked_at, created_at
+		FROM organization_invitations
+		WHERE organization_id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*models.OrganizationInvitation{}
+	for rows.Next() {
+		var inv models.OrganizationInvitation
+		if err := rows.Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.InvitedBy,
+			&inv.ExpiresAt, &inv.AcceptedAt, &inv.RevokedAt, &inv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, &inv)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, user *models.User, orgID, invitationID uuid.UUID) error {
+	if _, err := s.requireManager(ctx, user, orgID); err != nil {
+		return err
+	}
+
+	result, err := s.db.Pool.Exec(ctx, `
+		UPDATE organization_invitations SET revoked_at = $3
+		WHERE id = $1 AND organization_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL`,
+		invitationID, orgID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrInvitationNotFound
+	}
+
+	s.logger.Info("Organization invitation revoked",
+		zap.String("organization_id", orgID.String()),
+		zap.String("invitation_id", invitationID.String()),
+		zap.String("revoked_by", user.ID.String()))
+	return nil
+}
+
+// AcceptInvitation adds the user to the inviting organization. The
+// invitation must have been issued to the user's email address, which is
+// read from the database since session tokens do not carry it.
+func (s *Service) AcceptInvitation(ctx context.Context, user *models.User, token string) (*models.OrganizationMember, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var username, email string
+	err = tx.QueryRow(ctx, "SELECT username, email FROM users WHERE id = $1", user.ID).Scan(&username, &email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var invitationID, orgID uuid.UUID
+	var role models.OrgRole
+	err = tx.QueryRow(ctx, `
+		SELECT i.id, i.organization_id, i.role
+		FROM organization_invitations i
+		JOIN organizations o ON o.id = i.organization_id AND o.deleted_at IS NULL
+		WHERE i.token_hash = $1 AND LOWER(i.email) = LOWER($2)
+		  AND i.accepted_at IS NULL AND i.revoked_at IS NULL AND i.expires_at > NOW()
+		FOR UPDATE OF i`,
+		hashInvitationToken(token), email).Scan(&invitationID, &orgID, &role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	now := time.Now()
+	member := &models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Username:       username,
+		Email:          email,
+		Role:           role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	// Re-activate a previously removed membership instead of inserting a
+	// second row for the same user.
+	err = tx.QueryRow(ctx, `
+		INSERT INTO organization_users (organization_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (organization_id, user_id) DO UPDATE
+			SET role = EXCLUDED.role, deleted_at = NULL, updated_at = EXCLUDED.updated_at
+			WHERE organization_users.deleted_at IS NOT NULL
+		RETURNING id`,
+		orgID, user.ID, role, now).Scan(&member.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAlreadyMember
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE organization_invitations SET accepted_at = $2, accepted_by = $3 WHERE id = $1",
+		invitationID, now, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit invitation: %w", err)
+	}
+
+	s.logger.Info("Organization invitation accepted",
+		zap.String("organization_id", orgID.String()),
+		zap.String("invitation_id", invitationID.String()),
+		zap.String("user_id", user.ID.String()))
+
+	return member, nil
+}
+
+func generateInvitationToken() (string, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	tokenString := hex.EncodeToString(tokenBytes)
+	return tokenString, hashInvitationToken(tokenString), nil
+}
+
+func hashInvitationToken(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
+
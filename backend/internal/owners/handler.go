@@ -0,0 +1,184 @@
+package owners
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List owners
+// @Description Page through the owners registry, newest first
+// @Tags owners
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search by name, email or phone"
+// @Param limit query int false "Number of owners to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of matching owners"
+// @Success 200 {object} pagination.Page[models.Owner]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /owners [get]
+func (h *Handler) ListOwners(c *gin.Context) {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owners, err := h.service.ListOwners(c.Request.Context(), c.Query("q"), params)
+	if err != nil {
+		h.respondError(c, "Failed to list owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owners)
+}
+
+// @Summary Create an owner
+// @Description Register a new owner
+// @Tags owners
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateOwnerRequest true "Owner data"
+// @Success 201 {object} models.Owner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /owners [post]
+func (h *Handler) CreateOwner(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOwnerRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	owner, err := h.service.CreateOwner(c.Request.Context(), user, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create owner", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, owner)
+}
+
+// @Summary Get an owner
+// @Description Get owner details
+// @Tags owners
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Owner ID"
+// @Success 200 {object} models.Owner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /owners/{id} [get]
+func (h *Handler) GetOwner(c *gin.Context) {
+	id, ok := api.ParamID(c, "id", "Invalid owner ID")
+	if !ok {
+		return
+	}
+
+	owner, err := h.service.GetOwner(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, "Failed to get owner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
+
+// @Summary Update an owner
+// @Description Update owner details
+// @Tags owners
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Owner ID"
+// @Param request body models.UpdateOwnerRequest true "Owner update data"
+// @Success 200 {object} models.Owner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /owners/{id} [put]
+func (h *Handler) UpdateOwner(c *gin.Context) {
+	id, ok := api.ParamID(c, "id", "Invalid owner ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOwnerRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	owner, err := h.service.UpdateOwner(c.Request.Context(), id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update owner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
+
+// @Summary Delete an owner
+// @Description Remove an owner from the registry. Owners holding shares must be reassigned first.
+// @Tags owners
+// @Security BearerAuth
+// @Param id path string true "Owner ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /owners/{id} [delete]
+func (h *Handler) DeleteOwner(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := api.ParamID(c, "id", "Invalid owner ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOwner(c.Request.Context(), user, id); err != nil {
+		h.respondError(c, "Failed to delete owner", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrOwnerNotFound, Status: http.StatusNotFound, Message: "Owner not found"},
+		api.ErrorResponse{Err: ErrOwnerInUse, Status: http.StatusConflict},
+	)
+}
+
@@ -0,0 +1,216 @@
+package owners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOwnerNotFound = errors.New("owner not found")
+	ErrOwnerInUse    = errors.New("owner still holds project or house shares")
+)
+
+type Service struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewService(database *db.Database, logger *zap.Logger) *Service {
+	return &Service{
+		db:     database,
+		logger: logger,
+	}
+}
+
+const ownerColumns = `id, name, email, phone, metadata, created_at, updated_at`
+
+func scanOwner(row pgx.Row) (*models.Owner, error) {
+	var owner models.Owner
+	err := row.Scan(&owner.ID, &owner.Name, &owner.Email, &owner.Phone, &owner.Metadata,
+		&owner.CreatedAt, &owner.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &owner, nil
+}
+
+// ListOwners pages through the registry, newest first. query matches the
+// name, email or phone.
+func (s *Service) ListOwners(ctx context.Context, query string, params *pagination.Params) (*pagination.Page[*models.Owner], error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if query != "" {
+		args = append(args, "%"+query+"%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d OR phone ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+	filterArgs := args
+
+	cursor, args := params.KeysetCondition(args)
+	args = append(args, params.Fetch())
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+ownerColumns+`
+		FROM owners
+		WHERE `+where+` AND `+cursor+`
+		ORDER BY `+pagination.KeysetOrder+`
+		LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list owners: %w", err)
+	}
+	defer rows.Close()
+
+	var owners []*models.Owner
+	for rows.Next() {
+		owner, err := scanOwner(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list owners: %w", err)
+	}
+
+	page := pagination.NewPage(owners, params, func(last *models.Owner) pagination.Cursor {
+		return pagination.After(last.CreatedAt, last.ID)
+	})
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM owners WHERE "+where, filterArgs...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count owners: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (s *Service) GetOwner(ctx context.Context, id uuid.UUID) (*models.Owner, error) {
+	owner, err := scanOwner(s.db.Pool.QueryRow(ctx, `
+		SELECT `+ownerColumns+`
+		FROM owners
+		WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOwnerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner: %w", err)
+	}
+	return owner, nil
+}
+
+func (s *Service) CreateOwner(ctx context.Context, user *models.User, req *models.CreateOwnerRequest) (*models.Owner, error) {
+	owner := &models.Owner{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Metadata:  models.JSONB(req.Metadata),
+		CreatedAt: time.Now(),
+	}
+	owner.UpdatedAt = owner.CreatedAt
+
+	_, err := s.db.Pool.Exec(ctx, `
+		INSERT INTO owners (id, name, email, phone, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		owner.ID, owner.Name, owner.Email, owner.Phone, owner.Metadata, owner.CreatedAt, owner.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create owner: %w", err)
+	}
+
+	s.logger.Info("Owner created",
+		zap.String("owner_id", owner.ID.String()),
+		zap.String("created_by", user.ID.String()))
+	return owner, nil
+}
+
+func (s *Service) UpdateOwner(ctx context.Context, id uuid.UUID, req *models.UpdateOwnerRequest) (*models.Owner, error) {
+	owner, err := s.GetOwner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		owner.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Email != nil {
+		owner.Email = req.Email
+	}
+	if req.Phone != nil {
+		owner.Phone = req.Phone
+	}
+	if req.Metadata != nil {
+		owner.Metadata = models.JSONB(req.Metadata)
+	}
+	owner.UpdatedAt = time.Now()
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE owners SET name = $2, email = $3, phone = $4, metadata = $5, updated_at = $6
+		WHERE id = $1 AND deleted_at IS NULL`,
+		owner.ID, owner.Name, owner.Email, owner.Phone, owner.Metadata, owner.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update owner: %w", err)
+	}
+
+	return owner, nil
+}
+
+// DeleteOwner removes the owner from the registry. Owners that still hold
+// shares must be reassigned first, since removing them would break the
+// 100% total of the projects and houses they own.
+func (s *Service) DeleteOwner(ctx context.Context, user *models.User, id uuid.UUID) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ownerID uuid.UUID
+	err = tx.QueryRow(ctx, "SELECT id FROM owners WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrOwnerNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock owner: %w", err)
+	}
+
+	var inUse bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM project_owners WHERE owner_id = $1)
+		    OR EXISTS (SELECT 1 FROM house_owners WHERE owner_id = $1)`, id).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("failed to check owner shares: %w", err)
+	}
+	if inUse {
+		return ErrOwnerInUse
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE owners SET deleted_at = NOW() WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit owner deletion: %w", err)
+	}
+
+	s.logger.Info("Owner deleted",
+		zap.String("owner_id", id.String()),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
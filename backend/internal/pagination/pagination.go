@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+
+	// KeysetOrder is the ORDER BY clause keyset cursors page through.
+	// Tables using it need created_at and id columns.
+	KeysetOrder = "created_at DESC, id DESC"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks where the next page starts. Lists in KeysetOrder carry the
+// created_at and id of the last row returned; lists with a custom order
+// fall back to an offset. Clients only ever see the encoded form.
+type Cursor struct {
+	CreatedAt *time.Time `json:"c,omitempty"`
+	ID        *uuid.UUID `json:"i,omitempty"`
+	Offset    int        `json:"o,omitempty"`
+}
+
+// After returns a keyset cursor positioned after the given row
+func After(createdAt time.Time, id uuid.UUID) Cursor {
+	return Cursor{CreatedAt: &createdAt, ID: &id}
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if (cursor.CreatedAt == nil) != (cursor.ID == nil) || cursor.Offset < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// Params are the paging options of a list request
+type Params struct {
+	Limit        int
+	Cursor       *Cursor
+	IncludeTotal bool
+}
+
+// ParseParams reads limit, cursor and include_total from the query string
+func ParseParams(c *gin.Context) (*Params, error) {
+	params := &Params{Limit: DefaultLimit}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("invalid limit")
+		}
+		if limit > MaxLimit {
+			limit = MaxLimit
+		}
+		params.Limit = limit
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := Decode(value)
+		if err != nil {
+			return nil, err
+		}
+		params.Cursor = cursor
+	}
+
+	if value := c.Query("include_total"); value != "" {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("invalid include_total value")
+		}
+		params.IncludeTotal = includeTotal
+	}
+
+	return params, nil
+}
+
+// Offset is the number of rows to skip for lists that page by offset
+func (p *Params) Offset() int {
+	if p.Cursor == nil {
+		return 0
+	}
+	return p.Cursor.Offset
+}
+
+// Fetch is the number of rows to query: one more than the page size, so
+// NewPage can tell whether another page follows.
+func (p *Params) Fetch() int {
+	return p.Limit + 1
+}
+
+// KeysetCondition returns the condition selecting the rows after the
+// cursor in KeysetOrder, or "TRUE" on the first page. Placeholders continue
+// after the given args.
+func (p *Params) KeysetCondition(args []interface{}) (string, []interface{}) {
+	if p.Cursor == nil || p.Cursor.CreatedAt == nil {
+		return "TRUE", args
+	}
+
+	args = append(args, *p.Cursor.CreatedAt, *p.Cursor.ID)
+	return fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)), args
+}
+
+// Page is the response envelope of paginated list endpoints
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      *int64  `json:"total,omitempty"`
+}
+
+// NewPage builds a page from up to Fetch() rows. When there are more rows
+// than the page size, the extra row is dropped and next is called with the
+// last row kept to build the cursor.
+func NewPage[T any](items []T, params *Params, next func(last T) Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if len(items) > params.Limit {
+		page.Items = items[:params.Limit]
+		cursor := next(page.Items[len(page.Items)-1]).Encode()
+		page.NextCursor = &cursor
+	}
+
+	return page
+}
+
+// OffsetCursor returns next for lists that page by offset
+func OffsetCursor[T any](params *Params) func(T) Cursor {
+	return func(T) Cursor {
+		return Cursor{Offset: params.Offset() + params.Limit}
+	}
+}
+
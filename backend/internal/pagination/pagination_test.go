@@ -0,0 +1,158 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	id := uuid.New()
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"keyset", After(createdAt, id)},
+		{"offset", Cursor{Offset: 150}},
+		{"empty", Cursor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := Decode(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if decoded.Offset != tt.cursor.Offset {
+				t.Errorf("Offset = %d, want %d", decoded.Offset, tt.cursor.Offset)
+			}
+			if (decoded.CreatedAt == nil) != (tt.cursor.CreatedAt == nil) ||
+				decoded.CreatedAt != nil && !decoded.CreatedAt.Equal(*tt.cursor.CreatedAt) {
+				t.Errorf("CreatedAt = %v, want %v", decoded.CreatedAt, tt.cursor.CreatedAt)
+			}
+			if !reflect.DeepEqual(decoded.ID, tt.cursor.ID) {
+				t.Errorf("ID = %v, want %v", decoded.ID, tt.cursor.ID)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"o":1}`))},
+		{"not json", encode("offset=1")},
+		{"negative offset", encode(`{"o":-1}`)},
+		{"time without id", encode(`{"c":"2024-03-01T00:00:00Z"}`)},
+		{"id without time", encode(`{"i":"` + uuid.NewString() + `"}`)},
+		{"bad id", encode(`{"c":"2024-03-01T00:00:00Z","i":"nope"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cursor := Cursor{Offset: 20}.Encode()
+
+	tests := []struct {
+		query        string
+		limit        int
+		offset       int
+		includeTotal bool
+		wantErr      bool
+	}{
+		{"", DefaultLimit, 0, false, false},
+		{"limit=10", 10, 0, false, false},
+		{"limit=1000", MaxLimit, 0, false, false},
+		{"limit=0", 0, 0, false, true},
+		{"limit=abc", 0, 0, false, true},
+		{"cursor=" + cursor, DefaultLimit, 20, false, false},
+		{"cursor=***", 0, 0, false, true},
+		{"include_total=true", DefaultLimit, 0, true, false},
+		{"include_total=maybe", 0, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+tt.query, nil)
+
+		params, err := ParseParams(c)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseParams(%q) succeeded, want error", tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseParams(%q): %v", tt.query, err)
+			continue
+		}
+		if params.Limit != tt.limit || params.Offset() != tt.offset || params.IncludeTotal != tt.includeTotal {
+			t.Errorf("ParseParams(%q) = limit %d, offset %d, total %v; want %d, %d, %v",
+				tt.query, params.Limit, params.Offset(), params.IncludeTotal, tt.limit, tt.offset, tt.includeTotal)
+		}
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	params := &Params{Limit: 10}
+	if cond, args := params.KeysetCondition([]interface{}{"x"}); cond != "TRUE" || len(args) != 1 {
+		t.Errorf("first page = %q, %v", cond, args)
+	}
+
+	params.Cursor = &Cursor{Offset: 10}
+	if cond, _ := params.KeysetCondition(nil); cond != "TRUE" {
+		t.Errorf("offset cursor = %q, want TRUE", cond)
+	}
+
+	cursor := After(time.Now(), uuid.New())
+	params.Cursor = &cursor
+	cond, args := params.KeysetCondition([]interface{}{"x"})
+	if cond != "(created_at, id) < ($2, $3)" || len(args) != 3 {
+		t.Errorf("keyset cursor = %q, %d args", cond, len(args))
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	params := &Params{Limit: 2, Cursor: &Cursor{Offset: 4}}
+
+	page := NewPage([]int{1, 2}, params, OffsetCursor[int](params))
+	if len(page.Items) != 2 || page.NextCursor != nil {
+		t.Errorf("last page = %v, next %v", page.Items, page.NextCursor)
+	}
+
+	page = NewPage([]int{1, 2, 3}, params, OffsetCursor[int](params))
+	if !reflect.DeepEqual(page.Items, []int{1, 2}) || page.NextCursor == nil {
+		t.Fatalf("full page = %v, next %v", page.Items, page.NextCursor)
+	}
+	next, err := Decode(*page.NextCursor)
+	if err != nil || next.Offset != 6 {
+		t.Errorf("next cursor = %+v, %v, want offset 6", next, err)
+	}
+
+	page = NewPage[int](nil, params, OffsetCursor[int](params))
+	if page.Items == nil || len(page.Items) != 0 {
+		t.Errorf("empty page items = %#v, want []", page.Items)
+	}
+}
+
@@ -0,0 +1,179 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+
+	"project-management-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultBudgetBuckets are the budget bucket boundaries used unless the
+// request gives its own
+var DefaultBudgetBuckets = []float64{100000, 500000, 1000000, 5000000}
+
+// overdueListLimit caps the overdue projects listed in analytics
+const overdueListLimit = 50
+
+// overdueCondition selects open projects past their end date. Cancelled
+// projects are closed too, so they are never overdue.
+const overdueCondition = `end_date < CURRENT_DATE AND COALESCE(status, '') NOT IN ('completed', 'cancelled')`
+
+// GetAnalytics aggregates the projects matching the filter. Sorting in the
+// filter is ignored. bucketBounds must be in ascending order.
+func (s *Service) GetAnalytics(ctx context.Context, user *models.User, filter *ListProjectsFilter, bucketBounds []float64) (*models.ProjectAnalytics, error) {
+	if err := s.resolveMetadataFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	where, args := filter.where(user, nil)
+
+	// One snapshot for all the aggregates so they add up
+	tx, err := s.db.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	analytics := &models.ProjectAnalytics{}
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(budget), 0),
+		       AVG(end_date - start_date) FILTER (WHERE start_date IS NOT NULL AND end_date IS NOT NULL)
+		FROM projects
+		WHERE `+where, args...).Scan(&analytics.TotalProjects, &analytics.TotalBudget, &analytics.AverageDurationDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project totals: %w", err)
+	}
+
+	groups := []struct {
+		target *[]models.AnalyticsGroup
+		key    string
+		order  string
+	}{
+		{&analytics.ByStatus, "status", "key"},
+		{&analytics.ByCity, "city", "count DESC, key"},
+		{&analytics.ByState, "state", "count DESC, key"},
+		{&analytics.ByStartMonth, "to_char(start_date, 'YYYY-MM')", "key"},
+	}
+	for _, group := range groups {
+		*group.target, err = groupProjects(ctx, tx, where, args, group.key, group.order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	analytics.Overdue, err = overdueProjects(ctx, tx, where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics.BudgetBuckets, err = budgetBuckets(ctx, tx, where, args, bucketBounds)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+func groupProjects(ctx context.Context, tx pgx.Tx, where string, args []interface{}, key, order string) ([]models.AnalyticsGroup, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+key+` AS key, COUNT(*) AS count, COALESCE(SUM(budget), 0)
+		FROM projects
+		WHERE `+where+`
+		GROUP BY 1
+		ORDER BY `+order+` NULLS LAST`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to group projects by %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	groups := []models.AnalyticsGroup{}
+	for rows.Next() {
+		var group models.AnalyticsGroup
+		if err := rows.Scan(&group.Key, &group.Count, &group.TotalBudget); err != nil {
+			return nil, fmt.Errorf("failed to scan project group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func overdueProjects(ctx context.Context, tx pgx.Tx, where string, args []interface{}) (models.OverdueSummary, error) {
+	summary := models.OverdueSummary{Projects: []models.OverdueProject{}}
+
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(budget), 0)
+		FROM projects
+		WHERE `+where+` AND `+overdueCondition, args...).Scan(&summary.Count, &summary.TotalBudget)
+	if err != nil {
+		return summary, fmt.Errorf("failed to count overdue projects: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, status, end_date, CURRENT_DATE - end_date
+		FROM projects
+		WHERE `+where+` AND `+overdueCondition+`
+		ORDER BY end_date, id
+		LIMIT `+fmt.Sprint(overdueListLimit),
+		args...)
+	if err != nil {
+		return summary, fmt.Errorf("failed to list overdue projects: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var project models.OverdueProject
+		if err := rows.Scan(&project.ID, &project.Name, &project.Status, &project.EndDate, &project.DaysOverdue); err != nil {
+			return summary, fmt.Errorf("failed to scan overdue project: %w", err)
+		}
+		summary.Projects = append(summary.Projects, project)
+	}
+	return summary, rows.Err()
+}
+
+// budgetBuckets counts projects per budget range. width_bucket returns 0
+// below the first bound and len(bounds) at or above the last one, so there
+// is one more bucket than bounds, plus one for projects without a budget.
+func budgetBuckets(ctx context.Context, tx pgx.Tx, where string, args []interface{}, bounds []float64) ([]models.BudgetBucket, error) {
+	buckets := make([]models.BudgetBucket, len(bounds)+2)
+	for i := 0; i <= len(bounds); i++ {
+		if i > 0 {
+			buckets[i].Min = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			buckets[i].Max = &bounds[i]
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT width_bucket(budget::float8, `+fmt.Sprintf("$%d", len(args)+1)+`::float8[]) AS bucket,
+		       COUNT(*), COALESCE(SUM(budget), 0)
+		FROM projects
+		WHERE `+where+`
+		GROUP BY 1`,
+		append(append([]interface{}{}, args...), bounds)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bucket project budgets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket *int
+		var count int64
+		var total float64
+		if err := rows.Scan(&bucket, &count, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan budget bucket: %w", err)
+		}
+
+		// Projects without a budget land in the last entry
+		index := len(buckets) - 1
+		if bucket != nil {
+			index = *bucket
+		}
+		buckets[index].Count = count
+		buckets[index].TotalBudget = total
+	}
+	return buckets, rows.Err()
+}
+
@@ -0,0 +1,94 @@
+package projects
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// projectETag is the strong entity tag of a project: its version number,
+// which changes on every update.
+func projectETag(project *models.Project) string {
+	return fmt.Sprintf(`"%d"`, project.Version)
+}
+
+// listETag is a weak entity tag for a page of projects, derived from the
+// IDs and versions it contains and from its total and next cursor, which
+// change when projects are added or removed elsewhere in the list.
+func listETag(page *pagination.Page[*models.Project]) string {
+	h := sha256.New()
+	for _, p := range page.Items {
+		fmt.Fprintf(h, "%s:%d;", p.ID, p.Version)
+	}
+	if page.Total != nil {
+		fmt.Fprintf(h, "total:%d;", *page.Total)
+	}
+	if page.NextCursor != nil {
+		fmt.Fprintf(h, "next:%s;", *page.NextCursor)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:16] + `"`
+}
+
+// parseIfMatch returns the versions listed in an If-Match header. wildcard is
+// true for "*". Weak tags never satisfy If-Match and are skipped.
+func parseIfMatch(header string) (versions []int, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		version, err := strconv.Atoi(strings.Trim(tag, `"`))
+		if err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false
+}
+
+// matchesIfNoneMatch reports whether an If-None-Match header matches etag,
+// using the weak comparison RFC 7232 prescribes for GET.
+func matchesIfNoneMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersions reads the If-Match header of a write request. A nil
+// result means the write is unconditional. When the header names no version
+// that could match, it responds with 412 and returns false.
+func ifMatchVersions(c *gin.Context) ([]int, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return nil, true
+	}
+
+	versions, wildcard := parseIfMatch(header)
+	if wildcard {
+		return nil, true
+	}
+	if len(versions) == 0 {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": ErrVersionMismatch.Error()})
+		return nil, false
+	}
+	return versions, true
+}
+
@@ -0,0 +1,143 @@
+package projects
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestProjectETag(t *testing.T) {
+	if got := projectETag(&models.Project{Version: 7}); got != `"7"` {
+		t.Errorf("projectETag = %s, want \"7\"", got)
+	}
+}
+
+func TestListETag(t *testing.T) {
+	a := &models.Project{ID: uuid.New(), Version: 1}
+	b := &models.Project{ID: uuid.New(), Version: 1}
+	page := func(items ...*models.Project) *pagination.Page[*models.Project] {
+		return &pagination.Page[*models.Project]{Items: items}
+	}
+
+	etag := listETag(page(a, b))
+	if len(etag) != len(`W/"`)+16+1 || etag[:3] != `W/"` {
+		t.Fatalf("listETag = %s, want a weak tag", etag)
+	}
+	if listETag(page(a, b)) != etag {
+		t.Error("listETag is not deterministic")
+	}
+	if listETag(page(b, a)) == etag {
+		t.Error("listETag ignores order")
+	}
+
+	total := int64(2)
+	withTotal := page(a, b)
+	withTotal.Total = &total
+	if listETag(withTotal) == etag {
+		t.Error("listETag ignores the total")
+	}
+	otherTotal := int64(3)
+	withOtherTotal := page(a, b)
+	withOtherTotal.Total = &otherTotal
+	if listETag(withOtherTotal) == listETag(withTotal) {
+		t.Error("listETag ignores changes to the total")
+	}
+
+	cursor := "next"
+	withCursor := page(a, b)
+	withCursor.NextCursor = &cursor
+	if listETag(withCursor) == etag {
+		t.Error("listETag ignores the next cursor")
+	}
+
+	b.Version = 2
+	if listETag(page(a, b)) == etag {
+		t.Error("listETag ignores versions")
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		versions []int
+		wildcard bool
+	}{
+		{`"3"`, []int{3}, false},
+		{`"3", "5"`, []int{3, 5}, false},
+		{`*`, nil, true},
+		{`"3", *`, nil, true},
+		{`W/"3"`, nil, false},
+		{`W/"3", "4"`, []int{4}, false},
+		{`"abc"`, nil, false},
+		{``, nil, false},
+	}
+
+	for _, tt := range tests {
+		versions, wildcard := parseIfMatch(tt.header)
+		if !reflect.DeepEqual(versions, tt.versions) || wildcard != tt.wildcard {
+			t.Errorf("parseIfMatch(%q) = %v, %v, want %v, %v", tt.header, versions, wildcard, tt.versions, tt.wildcard)
+		}
+	}
+}
+
+func TestMatchesIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{``, `"3"`, false},
+		{`"3"`, `"3"`, true},
+		{`"4"`, `"3"`, false},
+		{`"4", "3"`, `"3"`, true},
+		{`W/"3"`, `"3"`, true},
+		{`"abc"`, `W/"abc"`, true},
+		{`*`, `"3"`, true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesIfNoneMatch(tt.header, tt.etag); got != tt.want {
+			t.Errorf("matchesIfNoneMatch(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header   string
+		versions []int
+		ok       bool
+	}{
+		{``, nil, true},
+		{`*`, nil, true},
+		{`"2"`, []int{2}, true},
+		{`W/"2"`, nil, false},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+		if tt.header != "" {
+			c.Request.Header.Set("If-Match", tt.header)
+		}
+
+		versions, ok := ifMatchVersions(c)
+		if !reflect.DeepEqual(versions, tt.versions) || ok != tt.ok {
+			t.Errorf("ifMatchVersions(%q) = %v, %v, want %v, %v", tt.header, versions, ok, tt.versions, tt.ok)
+		}
+		if !tt.ok && w.Code != http.StatusPreconditionFailed {
+			t.Errorf("ifMatchVersions(%q) status = %d, want 412", tt.header, w.Code)
+		}
+	}
+}
+
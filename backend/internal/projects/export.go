@@ -0,0 +1,213 @@
+package projects
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MaxExportProjects caps the projects in one export
+const MaxExportProjects = 10000
+
+var ErrExportTooLarge = fmt.Errorf("more than %d projects match; narrow the filters", MaxExportProjects)
+
+const (
+	GeoJSONContentType = "application/geo+json"
+	KMLContentType     = "application/vnd.google-earth.kml+xml"
+)
+
+// ExportProjects returns every project matching the filter that has
+// coordinates, in the order ListProjects would return them.
+func (s *Service) ExportProjects(ctx context.Context, user *models.User, filter *ListProjectsFilter) ([]*models.Project, error) {
+	if err := s.resolveMetadataFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	where, args := filter.where(user, nil)
+	order, args := filter.orderBy(args)
+	args = append(args, MaxExportProjects+1)
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+projectColumns+`
+		FROM projects
+		WHERE `+where+` AND latitude IS NOT NULL AND longitude IS NOT NULL
+		ORDER BY `+order+`
+		LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*models.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export projects: %w", err)
+	}
+
+	if len(projects) > MaxExportProjects {
+		return nil, ErrExportTooLarge
+	}
+	return projects, nil
+}
+
+// exportProperties are the attributes of a project in an export, with the
+// names used as GeoJSON properties and KML data fields.
+func exportProperties(p *models.Project) []exportProperty {
+	return []exportProperty{
+		{"id", p.ID.String()},
+		{"name", p.Name},
+		{"type", (*string)(p.Type)},
+		{"status", p.Status},
+		{"budget", p.Budget},
+		{"owner", p.OwnerName},
+		{"address", p.Address},
+		{"city", p.City},
+		{"state", p.State},
+		{"postal_code", p.PostalCode},
+		{"start_date", dateString(p.StartDate)},
+		{"end_date", dateString(p.EndDate)},
+		{"organization_id", uuidString(p.OrganizationID)},
+	}
+}
+
+type exportProperty struct {
+	name  string
+	value interface{}
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
+
+// GeoJSON (RFC 7946)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type string `json:"type"`
+	// Coordinates are longitude, latitude
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func newFeatureCollection(projects []*models.Project) *geoJSONFeatureCollection {
+	collection := &geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(projects)),
+	}
+	for _, p := range projects {
+		properties := map[string]interface{}{}
+		for _, property := range exportProperties(p) {
+			properties[property.name] = property.value
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			ID:   p.ID.String(),
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{*p.Longitude, *p.Latitude},
+			},
+			Properties: properties,
+		})
+	}
+	return collection
+}
+
+// KML 2.2
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Name       string         `xml:"Document>name"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID          string    `xml:"id,attr"`
+	Name        string    `xml:"name"`
+	Data        []kmlData `xml:"ExtendedData>Data"`
+	Coordinates string    `xml:"Point>coordinates"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+func newKMLDocument(projects []*models.Project) *kmlDocument {
+	doc := &kmlDocument{
+		Name:       "Projects",
+		Placemarks: make([]kmlPlacemark, 0, len(projects)),
+	}
+	for _, p := range projects {
+		placemark := kmlPlacemark{
+			ID:          "project-" + p.ID.String(),
+			Name:        p.Name,
+			Coordinates: formatCoordinate(*p.Longitude) + "," + formatCoordinate(*p.Latitude),
+		}
+		// KML data values are text; empty fields are left out
+		for _, property := range exportProperties(p) {
+			if value, ok := kmlValue(property.value); ok {
+				placemark.Data = append(placemark.Data, kmlData{Name: property.name, Value: value})
+			}
+		}
+		doc.Placemarks = append(doc.Placemarks, placemark)
+	}
+	return doc
+}
+
+func kmlValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// marshalKML encodes the document with an XML declaration
+func marshalKML(doc *kmlDocument) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode KML: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
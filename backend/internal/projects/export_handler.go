@@ -0,0 +1,139 @@
+package projects
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary Export projects as GeoJSON
+// @Description Export the located projects matching the list filters as a GeoJSON FeatureCollection of points
+// @Tags projects
+// @Produce application/geo+json
+// @Security BearerAuth
+// @Param q query string false "Full-text search over name, address, city and owner"
+// @Param status query string false "Comma-separated statuses"
+// @Param type query string false "Comma-separated project types: Colony, IndependentHouse, LinkHouse, HousingEstate"
+// @Param city query string false "City (case-insensitive)"
+// @Param state query string false "State (case-insensitive)"
+// @Param owner query string false "Owner name contains"
+// @Param organization_id query string false "Organization ID"
+// @Param budget_min query number false "Minimum budget"
+// @Param budget_max query number false "Maximum budget"
+// @Param start_from query string false "Start date on or after (YYYY-MM-DD)"
+// @Param start_to query string false "Start date on or before (YYYY-MM-DD)"
+// @Param end_from query string false "End date on or after (YYYY-MM-DD)"
+// @Param end_to query string false "End date on or before (YYYY-MM-DD)"
+// @Param metadata[key] query string false "Metadata key equals value, e.g. metadata[phase]=2. Fields defined by the metadata schema of a type compare by value"
+// @Param metadata_min[key] query string false "Minimum of a number or date field defined by a metadata schema"
+// @Param metadata_max[key] query string false "Maximum of a number or date field defined by a metadata schema"
+// @Param near query string false "Point to search around as latitude,longitude; requires radius_km"
+// @Param radius_km query number false "Search radius around near, in kilometres"
+// @Param bbox query string false "Bounding box as min_lng,min_lat,max_lng,max_lat"
+// @Param sort query string false "Comma-separated sort keys, prefix with - for descending"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/export.geojson [get]
+func (h *Handler) ExportGeoJSON(c *gin.Context) {
+	projects, ok := h.exportProjects(c)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(newFeatureCollection(projects))
+	if err != nil {
+		h.logger.Error("Failed to encode GeoJSON export", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export projects"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="projects.geojson"`)
+	c.Data(http.StatusOK, GeoJSONContentType, body)
+}
+
+// @Summary Export projects as KML
+// @Description Export the located projects matching the list filters as KML placemarks
+// @Tags projects
+// @Produce application/vnd.google-earth.kml+xml
+// @Security BearerAuth
+// @Param q query string false "Full-text search over name, address, city and owner"
+// @Param status query string false "Comma-separated statuses"
+// @Param type query string false "Comma-separated project types: Colony, IndependentHouse, LinkHouse, HousingEstate"
+// @Param city query string false "City (case-insensitive)"
+// @Param state query string false "State (case-insensitive)"
+// @Param owner query string false "Owner name contains"
+// @Param organization_id query string false "Organization ID"
+// @Param budget_min query number false "Minimum budget"
+// @Param budget_max query number false "Maximum budget"
+// @Param start_from query string false "Start date on or after (YYYY-MM-DD)"
+// @Param start_to query string false "Start date on or before (YYYY-MM-DD)"
+// @Param end_from query string false "End date on or after (YYYY-MM-DD)"
+// @Param end_to query string false "End date on or before (YYYY-MM-DD)"
+// @Param metadata[key] query string false "Metadata key equals value, e.g. metadata[phase]=2. Fields defined by the metadata schema of a type compare by value"
+// @Param metadata_min[key] query string false "Minimum of a number or date field defined by a metadata schema"
+// @Param metadata_max[key] query string false "Maximum of a number or date field defined by a metadata schema"
+// @Param near query string false "Point to search around as latitude,longitude; requires radius_km"
+// @Param radius_km query number false "Search radius around near, in kilometres"
+// @Param bbox query string false "Bounding box as min_lng,min_lat,max_lng,max_lat"
+// @Param sort query string false "Comma-separated sort keys, prefix with - for descending"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/export.kml [get]
+func (h *Handler) ExportKML(c *gin.Context) {
+	projects, ok := h.exportProjects(c)
+	if !ok {
+		return
+	}
+
+	body, err := marshalKML(newKMLDocument(projects))
+	if err != nil {
+		h.logger.Error("Failed to encode KML export", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export projects"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="projects.kml"`)
+	c.Data(http.StatusOK, KMLContentType, body)
+}
+
+// exportProjects loads the projects for an export, writing the error
+// response itself when it fails
+func (h *Handler) exportProjects(c *gin.Context) ([]*models.Project, bool) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	projects, err := h.service.ExportProjects(c.Request.Context(), user, filter)
+	if errors.Is(err, ErrInvalidFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if errors.Is(err, ErrExportTooLarge) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to export projects", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export projects"})
+		return nil, false
+	}
+
+	return projects, true
+}
@@ -0,0 +1,251 @@
+package projects
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+// sortColumns maps the sort keys accepted by ListProjects to columns
+var sortColumns = map[string]string{
+	"name":       "name",
+	"city":       "city",
+	"state":      "state",
+	"status":     "status",
+	"owner_name": "owner_name",
+	"budget":     "budget",
+	"start_date": "start_date",
+	"end_date":   "end_date",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// distanceSort orders by distance from the point of a radius filter
+const distanceSort = "distance"
+
+type SortField struct {
+	Key  string
+	Desc bool
+}
+
+// ListProjectsFilter narrows and orders ListProjects. Zero values mean
+// "no restriction".
+type ListProjectsFilter struct {
+	Statuses       []string
+	Types          []string
+	City           string
+	State          string
+	Owner          string
+	OrganizationID *uuid.UUID
+	BudgetMin      *float64
+	BudgetMax      *float64
+	StartFrom      *time.Time
+	StartTo        *time.Time
+	EndFrom        *time.Time
+	EndTo          *time.Time
+	Metadata       map[string]string
+	// MetadataMin and MetadataMax bound number and date fields defined by
+	// the metadata schemas
+	MetadataMin map[string]string
+	MetadataMax map[string]string
+	Query       string
+	Near        *GeoRadius
+	Within      *BoundingBox
+	Sort        []SortField
+
+	// metadataFields are the schema-defined metadata fields, set by
+	// Service.resolveMetadataFilter
+	metadataFields map[string]models.MetadataField
+}
+
+// where builds the WHERE clause for the filter, limited to live projects
+// the user can see. Placeholders continue after the given args.
+func (f *ListProjectsFilter) where(user *models.User, args []interface{}) (string, []interface{}) {
+	scope, args := scopeFilter(user, args)
+	conditions := []string{"deleted_at IS NULL", scope}
+
+	add := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(f.Statuses) > 0 {
+		conditions = append(conditions, "status = ANY("+add(f.Statuses)+")")
+	}
+	if len(f.Types) > 0 {
+		conditions = append(conditions, "type = ANY("+add(f.Types)+")")
+	}
+	if f.City != "" {
+		conditions = append(conditions, "LOWER(city) = LOWER("+add(f.City)+")")
+	}
+	if f.State != "" {
+		conditions = append(conditions, "LOWER(state) = LOWER("+add(f.State)+")")
+	}
+	if f.Owner != "" {
+		conditions = append(conditions, "owner_name ILIKE "+add("%"+f.Owner+"%"))
+	}
+	if f.OrganizationID != nil {
+		conditions = append(conditions, "organization_id = "+add(*f.OrganizationID))
+	}
+	if f.BudgetMin != nil {
+		conditions = append(conditions, "budget >= "+add(*f.BudgetMin))
+	}
+	if f.BudgetMax != nil {
+		conditions = append(conditions, "budget <= "+add(*f.BudgetMax))
+	}
+	if f.StartFrom != nil {
+		conditions = append(conditions, "start_date >= "+add(*f.StartFrom))
+	}
+	if f.StartTo != nil {
+		conditions = append(conditions, "start_date <= "+add(*f.StartTo))
+	}
+	if f.EndFrom != nil {
+		conditions = append(conditions, "end_date >= "+add(*f.EndFrom))
+	}
+	if f.EndTo != nil {
+		conditions = append(conditions, "end_date <= "+add(*f.EndTo))
+	}
+
+	for _, key := range sortedKeys(f.Metadata) {
+		conditions = append(conditions, f.metadataEquals(key, f.Metadata[key], add))
+	}
+	for _, key := range sortedKeys(f.MetadataMin) {
+		conditions = append(conditions, f.metadataCompare(key, ">=", f.MetadataMin[key], add))
+	}
+	for _, key := range sortedKeys(f.MetadataMax) {
+		conditions = append(conditions, f.metadataCompare(key, "<=", f.MetadataMax[key], add))
+	}
+
+	if f.Query != "" {
+		conditions = append(conditions, "search_vector @@ websearch_to_tsquery('simple', "+add(f.Query)+")")
+	}
+
+	if f.Within != nil {
+		box := *f.Within
+		conditions = append(conditions,
+			"latitude BETWEEN "+add(box.MinLatitude)+" AND "+add(box.MaxLatitude),
+			longitudeSQL(box, add(box.MinLongitude), add(box.MaxLongitude)))
+	}
+	if f.Near != nil {
+		box, withLongitude := f.Near.bounds()
+		conditions = append(conditions, "latitude BETWEEN "+add(box.MinLatitude)+" AND "+add(box.MaxLatitude))
+		if withLongitude {
+			conditions = append(conditions, longitudeSQL(box, add(box.MinLongitude), add(box.MaxLongitude)))
+		}
+		conditions = append(conditions,
+			distanceSQL(add(f.Near.Latitude), add(f.Near.Longitude))+" <= "+add(f.Near.RadiusKm))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// metadataEquals matches a metadata field. Schema-defined fields match by
+// JSON value through containment, so the GIN index applies and 5 matches
+// 5.0 but not "5"; other fields compare as text.
+func (f *ListProjectsFilter) metadataEquals(key, value string, add func(interface{}) string) string {
+	field, ok := f.metadataFields[key]
+	if !ok {
+		return "metadata->>" + add(key) + " = " + add(value)
+	}
+
+	var jsonValue string
+	switch field.Type {
+	case fieldNumber:
+		jsonValue = "to_jsonb(" + add(value) + "::text::numeric)"
+	case fieldBoolean:
+		jsonValue = "to_jsonb(" + add(value) + "::text::boolean)"
+	default:
+		jsonValue = "to_jsonb(" + add(value) + "::text)"
+	}
+	return "metadata @> jsonb_build_object(" + add(key) + "::text, " + jsonValue + ")"
+}
+
+// metadataCompare bounds a number or date field. Values of another JSON
+// type never match rather than failing the cast.
+func (f *ListProjectsFilter) metadataCompare(key, op, value string, add func(interface{}) string) string {
+	if f.metadataFields[key].Type == fieldNumber {
+		k := add(key)
+		return "CASE WHEN jsonb_typeof(metadata->" + k + ") = 'number' THEN (metadata->>" + k + ")::numeric END " +
+			op + " " + add(value) + "::text::numeric"
+	}
+	// ISO dates order correctly as text
+	k := add(key)
+	return "CASE WHEN jsonb_typeof(metadata->" + k + ") = 'string' THEN metadata->>" + k + " END " + op + " " + add(value) + "::text"
+}
+
+// sortedKeys keeps the generated SQL stable for identical filters
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keysetOrder reports whether the list uses pagination.KeysetOrder, which
+// is the case unless a sort or a search ranking is requested
+func (f *ListProjectsFilter) keysetOrder() bool {
+	return len(f.Sort) == 0 && f.Query == ""
+}
+
+// orderBy builds the ORDER BY clause. Without an explicit sort, search
+// results are ranked by relevance and everything else is newest first.
+// id is always the final tie-breaker so pages are stable.
+func (f *ListProjectsFilter) orderBy(args []interface{}) (string, []interface{}) {
+	if f.keysetOrder() {
+		return pagination.KeysetOrder, args
+	}
+
+	var terms []string
+	for _, field := range f.Sort {
+		column, ok := sortColumns[field.Key]
+		if field.Key == distanceSort && f.Near != nil {
+			args = append(args, f.Near.Latitude, f.Near.Longitude)
+			column, ok = distanceSQL(fmt.Sprintf("$%d", len(args)-1), fmt.Sprintf("$%d", len(args))), true
+		}
+		if !ok {
+			continue
+		}
+		if field.Desc {
+			terms = append(terms, column+" DESC NULLS LAST")
+		} else {
+			terms = append(terms, column+" ASC NULLS LAST")
+		}
+	}
+
+	if len(terms) == 0 {
+		args = append(args, f.Query)
+		terms = append(terms,
+			fmt.Sprintf("ts_rank(search_vector, websearch_to_tsquery('simple', $%d)) DESC", len(args)),
+			"created_at DESC")
+	}
+
+	return strings.Join(append(terms, "id"), ", "), args
+}
+
+// ParseSort parses a sort parameter such as "-budget,name". A leading "-"
+// sorts descending. "distance" is accepted too, for use with a radius
+// filter.
+func ParseSort(value string) ([]SortField, error) {
+	var fields []SortField
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := SortField{Key: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortColumns[field.Key]; !ok && field.Key != distanceSort {
+			return nil, fmt.Errorf("cannot sort by %q", field.Key)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
@@ -0,0 +1,85 @@
+package projects
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadiusKm is the mean Earth radius used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// kmPerDegree is the length of one degree of latitude
+const kmPerDegree = 2 * math.Pi * earthRadiusKm / 360
+
+// MaxRadiusKm is the largest search radius, half the Earth's circumference
+const MaxRadiusKm = math.Pi * earthRadiusKm
+
+// GeoRadius selects projects within RadiusKm of a point
+type GeoRadius struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// BoundingBox selects projects inside a latitude/longitude box. A box with
+// MinLongitude greater than MaxLongitude crosses the antimeridian.
+type BoundingBox struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+// distanceSQL is the haversine distance in kilometres between the project
+// and the point whose coordinates are the given placeholders. LEAST guards
+// asin against rounding just above 1 for antipodal points.
+func distanceSQL(lat, lon string) string {
+	return fmt.Sprintf(`(2 * %g * asin(LEAST(1, sqrt(
+		power(sin(radians(latitude - %[2]s) / 2), 2) +
+		cos(radians(%[2]s)) * cos(radians(latitude)) * power(sin(radians(longitude - %[3]s) / 2), 2)))))`,
+		earthRadiusKm, lat, lon)
+}
+
+// bounds returns the box enclosing the circle, which is checked before the
+// exact distance so that the coordinate index can narrow the scan. The
+// longitude range is omitted near the poles, where it spans every meridian.
+func (r *GeoRadius) bounds() (box BoundingBox, withLongitude bool) {
+	latDelta := r.RadiusKm / kmPerDegree
+	box.MinLatitude = math.Max(r.Latitude-latDelta, -90)
+	box.MaxLatitude = math.Min(r.Latitude+latDelta, 90)
+
+	if box.MinLatitude == -90 || box.MaxLatitude == 90 {
+		return box, false
+	}
+	// The widest longitude span of the circle, reached where a meridian
+	// touches it rather than at the centre's latitude
+	ratio := math.Sin(r.RadiusKm/earthRadiusKm) / math.Cos(r.Latitude*math.Pi/180)
+	if ratio >= 1 {
+		return box, false
+	}
+	lonDelta := math.Asin(ratio) * 180 / math.Pi
+
+	box.MinLongitude = wrapLongitude(r.Longitude - lonDelta)
+	box.MaxLongitude = wrapLongitude(r.Longitude + lonDelta)
+	return box, true
+}
+
+func wrapLongitude(lon float64) float64 {
+	if lon < -180 {
+		return lon + 360
+	}
+	if lon > 180 {
+		return lon - 360
+	}
+	return lon
+}
+
+// longitudeSQL matches longitudes between min and max, going east across the
+// antimeridian when min is greater than max
+func longitudeSQL(box BoundingBox, min, max string) string {
+	if box.MinLongitude > box.MaxLongitude {
+		return "(longitude >= " + min + " OR longitude <= " + max + ")"
+	}
+	return "longitude BETWEEN " + min + " AND " + max
+}
+
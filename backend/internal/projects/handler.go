@@ -1,10 +1,19 @@
 package projects
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/jsonpatch"
 	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -34,22 +43,42 @@ func NewHandler(service *Service, logger *zap.Logger) *Handler {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /projects [post]
 func (h *Handler) CreateProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
 	var req models.CreateProjectRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Invalid project creation request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
-	project, err := h.service.CreateProject(c.Request.Context(), &req)
+	project, err := h.service.CreateProject(c.Request.Context(), user, &req)
 	if err != nil {
-		h.logger.Error("Failed to create project", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
+		var fieldErrs validation.Errors
+		switch {
+		case errors.As(err, &fieldErrs):
+			validation.Respond(c, http.StatusUnprocessableEntity, fieldErrs)
+		case errors.Is(err, ErrOrganizationRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNoWriteAccess):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrOwnerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidOwnerShares):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to create project", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
+		}
 		return
 	}
 
+	c.Header("ETag", projectETag(project))
 	c.JSON(http.StatusCreated, project)
 }
 
@@ -59,12 +88,19 @@ func (h *Handler) CreateProject(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Project ID"
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Success 200 {object} models.Project
+// @Success 304
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /projects/{id} [get]
 func (h *Handler) GetProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -72,49 +108,96 @@ func (h *Handler) GetProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.service.GetProject(c.Request.Context(), id)
+	project, err := h.service.GetProject(c.Request.Context(), user, id)
 	if err != nil {
 		h.logger.Error("Failed to get project", zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
 
+	etag := projectETag(project)
+	c.Header("ETag", etag)
+	if matchesIfNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, project)
 }
 
 // @Summary List projects
-// @Description Get a list of all projects with pagination
+// @Description Search, filter and page through the projects visible to the current user
 // @Tags projects
 // @Produce json
 // @Security BearerAuth
+// @Param q query string false "Full-text search over name, address, city and owner"
+// @Param status query string false "Comma-separated statuses"
+// @Param type query string false "Comma-separated project types: Colony, IndependentHouse, LinkHouse, HousingEstate"
+// @Param city query string false "City (case-insensitive)"
+// @Param state query string false "State (case-insensitive)"
+// @Param owner query string false "Owner name contains"
+// @Param organization_id query string false "Organization ID"
+// @Param budget_min query number false "Minimum budget"
+// @Param budget_max query number false "Maximum budget"
+// @Param start_from query string false "Start date on or after (YYYY-MM-DD)"
+// @Param start_to query string false "Start date on or before (YYYY-MM-DD)"
+// @Param end_from query string false "End date on or after (YYYY-MM-DD)"
+// @Param end_to query string false "End date on or before (YYYY-MM-DD)"
+// @Param metadata[key] query string false "Metadata key equals value, e.g. metadata[phase]=2. Fields defined by the metadata schema of a type compare by value"
+// @Param metadata_min[key] query string false "Minimum of a number or date field defined by a metadata schema"
+// @Param metadata_max[key] query string false "Maximum of a number or date field defined by a metadata schema"
+// @Param near query string false "Point to search around as latitude,longitude; requires radius_km"
+// @Param radius_km query number false "Search radius around near, in kilometres"
+// @Param bbox query string false "Bounding box as min_lng,min_lat,max_lng,max_lat"
+// @Param sort query string false "Comma-separated sort keys, prefix with - for descending, e.g. -budget,name. distance sorts by distance from near"
 // @Param limit query int false "Number of projects to return" default(50)
-// @Param offset query int false "Number of projects to skip" default(0)
-// @Success 200 {array} models.Project
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of matching projects"
+// @Success 200 {object} pagination.Page[models.Project]
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /projects [get]
 func (h *Handler) ListProjects(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 50
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	projects, err := h.service.ListProjects(c.Request.Context(), limit, offset)
+	page, err := h.service.ListProjects(c.Request.Context(), user, filter, params)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if errors.Is(err, ErrInvalidFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to list projects", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get projects"})
 		return
 	}
 
-	c.JSON(http.StatusOK, projects)
+	etag := listETag(page)
+	c.Header("ETag", etag)
+	if matchesIfNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 // @Summary Update a project
@@ -124,14 +207,22 @@ func (h *Handler) ListProjects(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails with 412 if the project changed since"
 // @Param request body models.UpdateProjectRequest true "Project update data"
 // @Success 200 {object} models.Project
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 422 {object} map[string]interface{}
 // @Router /projects/{id} [put]
 func (h *Handler) UpdateProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -139,25 +230,113 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 		return
 	}
 
+	expectedVersions, ok := ifMatchVersions(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateProjectRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Invalid project update request", zap.Error(err))
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	project, err := h.service.UpdateProject(c.Request.Context(), user, id, &req, expectedVersions)
+	if err != nil {
+		var fieldErrs validation.Errors
+		switch {
+		case errors.As(err, &fieldErrs):
+			validation.Respond(c, http.StatusUnprocessableEntity, fieldErrs)
+		case errors.Is(err, ErrNoWriteAccess):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrVersionMismatch):
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to update project", zap.Error(err))
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		}
+		return
+	}
+
+	c.Header("ETag", projectETag(project))
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary Patch a project
+// @Description Partially update a project with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902). Null clears a field; metadata and documents merge recursively. application/json is treated as a merge patch.
+// @Tags projects
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails with 412 if the project changed since"
+// @Param request body object true "Merge patch object or JSON Patch operation array"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id} [patch]
+func (h *Handler) PatchProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	expectedVersions, ok := ifMatchVersions(c)
+	if !ok {
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType == "application/json" {
+		contentType = jsonpatch.MergePatchContentType
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
 		return
 	}
 
-	project, err := h.service.UpdateProject(c.Request.Context(), id, &req)
+	project, err := h.service.PatchProject(c.Request.Context(), user, id, contentType, patch, expectedVersions)
 	if err != nil {
-		h.logger.Error("Failed to update project", zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		var fieldErrs validation.Errors
+		switch {
+		case errors.As(err, &fieldErrs):
+			validation.Respond(c, http.StatusUnprocessableEntity, fieldErrs)
+		case errors.Is(err, ErrUnsupportedPatch):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, jsonpatch.ErrInvalidPatch), errors.Is(err, ErrInvalidProject):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNoWriteAccess):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrVersionMismatch):
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to patch project", zap.Error(err))
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		}
 		return
 	}
 
+	c.Header("ETag", projectETag(project))
 	c.JSON(http.StatusOK, project)
 }
 
 // @Summary Delete a project
-// @Description Delete a project by ID
+// @Description Move a project to the trash. It can be restored until it is purged.
 // @Tags projects
 // @Produce json
 // @Security BearerAuth
@@ -169,6 +348,11 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /projects/{id} [delete]
 func (h *Handler) DeleteProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -176,8 +360,12 @@ func (h *Handler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteProject(c.Request.Context(), id)
+	err = h.service.DeleteProject(c.Request.Context(), user, id)
 	if err != nil {
+		if errors.Is(err, ErrNoWriteAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to delete project", zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
@@ -195,7 +383,12 @@ func (h *Handler) DeleteProject(c *gin.Context) {
 // @Failure 401 {object} map[string]string
 // @Router /projects/stats [get]
 func (h *Handler) GetProjectStats(c *gin.Context) {
-	count, err := h.service.GetProjectCount(c.Request.Context())
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.service.GetProjectCount(c.Request.Context(), user)
 	if err != nil {
 		h.logger.Error("Failed to get project count", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get statistics"})
@@ -209,3 +402,224 @@ func (h *Handler) GetProjectStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// @Summary Get project analytics
+// @Description Counts and budget totals by status, city, state and start month, average duration, overdue projects and budget buckets. Accepts the ListProjects filters.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Full-text search over name, address, city and owner"
+// @Param status query string false "Comma-separated statuses"
+// @Param type query string false "Comma-separated project types: Colony, IndependentHouse, LinkHouse, HousingEstate"
+// @Param city query string false "City (case-insensitive)"
+// @Param state query string false "State (case-insensitive)"
+// @Param owner query string false "Owner name contains"
+// @Param organization_id query string false "Organization ID"
+// @Param budget_min query number false "Minimum budget"
+// @Param budget_max query number false "Maximum budget"
+// @Param start_from query string false "Start date on or after (YYYY-MM-DD)"
+// @Param start_to query string false "Start date on or before (YYYY-MM-DD)"
+// @Param end_from query string false "End date on or after (YYYY-MM-DD)"
+// @Param end_to query string false "End date on or before (YYYY-MM-DD)"
+// @Param near query string false "Point to search around as latitude,longitude; requires radius_km"
+// @Param radius_km query number false "Search radius around near, in kilometres"
+// @Param bbox query string false "Bounding box as min_lng,min_lat,max_lng,max_lat"
+// @Param budget_buckets query string false "Comma-separated ascending bucket boundaries" default(100000,500000,1000000,5000000)
+// @Success 200 {object} models.ProjectAnalytics
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /projects/analytics [get]
+func (h *Handler) GetProjectAnalytics(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bounds := DefaultBudgetBuckets
+	if value := c.Query("budget_buckets"); value != "" {
+		bounds = nil
+		for _, part := range strings.Split(value, ",") {
+			bound, err := parseNumber(strings.TrimSpace(part))
+			if err != nil || (len(bounds) > 0 && bound <= bounds[len(bounds)-1]) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "budget_buckets must be ascending numbers"})
+				return
+			}
+			bounds = append(bounds, bound)
+		}
+	}
+
+	analytics, err := h.service.GetAnalytics(c.Request.Context(), user, filter, bounds)
+	if errors.Is(err, ErrInvalidFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get project analytics", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// parseListFilter reads the ListProjects filter and sort parameters from
+// the query string. Paging is left to the caller.
+func parseListFilter(c *gin.Context) (*ListProjectsFilter, error) {
+	filter := &ListProjectsFilter{
+		City:     strings.TrimSpace(c.Query("city")),
+		State:    strings.TrimSpace(c.Query("state")),
+		Owner:    strings.TrimSpace(c.Query("owner")),
+		Query:    strings.TrimSpace(c.Query("q")),
+		Metadata: c.QueryMap("metadata"),
+	}
+	if values := c.QueryMap("metadata_min"); len(values) > 0 {
+		filter.MetadataMin = values
+	}
+	if values := c.QueryMap("metadata_max"); len(values) > 0 {
+		filter.MetadataMax = values
+	}
+
+	for _, value := range c.QueryArray("status") {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if status == "" {
+				continue
+			}
+			if !models.IsValidProjectStatus(status) {
+				return nil, fmt.Errorf("invalid status %q", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	for _, value := range c.QueryArray("type") {
+		for _, projectType := range strings.Split(value, ",") {
+			projectType = strings.TrimSpace(projectType)
+			if projectType == "" {
+				continue
+			}
+			if !models.ProjectType(projectType).IsValid() {
+				return nil, fmt.Errorf("invalid type %q", projectType)
+			}
+			filter.Types = append(filter.Types, projectType)
+		}
+	}
+
+	if value := c.Query("organization_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errors.New("invalid organization_id")
+		}
+		filter.OrganizationID = &id
+	}
+
+	for name, target := range map[string]**float64{
+		"budget_min": &filter.BudgetMin,
+		"budget_max": &filter.BudgetMax,
+	} {
+		if value := c.Query(name); value != "" {
+			budget, err := parseNumber(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			*target = &budget
+		}
+	}
+
+	for name, target := range map[string]**time.Time{
+		"start_from": &filter.StartFrom,
+		"start_to":   &filter.StartTo,
+		"end_from":   &filter.EndFrom,
+		"end_to":     &filter.EndTo,
+	} {
+		if value := c.Query(name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s, expected YYYY-MM-DD", name)
+			}
+			*target = &date
+		}
+	}
+
+	if value := c.Query("near"); value != "" {
+		point, err := parseCoordinates(value, 2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid near, expected latitude,longitude: %w", err)
+		}
+		radius, err := parseNumber(c.Query("radius_km"))
+		if err != nil || radius <= 0 || radius > MaxRadiusKm {
+			return nil, fmt.Errorf("radius_km must be a number of kilometres between 0 and %.0f", MaxRadiusKm)
+		}
+		filter.Near = &GeoRadius{Latitude: point[0], Longitude: point[1], RadiusKm: radius}
+	}
+
+	if value := c.Query("bbox"); value != "" {
+		box, err := parseCoordinates(value, 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bbox, expected min_lng,min_lat,max_lng,max_lat: %w", err)
+		}
+		if box[1] > box[3] {
+			return nil, errors.New("invalid bbox, min_lat is greater than max_lat")
+		}
+		filter.Within = &BoundingBox{MinLongitude: box[0], MinLatitude: box[1], MaxLongitude: box[2], MaxLatitude: box[3]}
+	}
+
+	sort, err := ParseSort(c.Query("sort"))
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range sort {
+		if field.Key == distanceSort && filter.Near == nil {
+			return nil, errors.New("sorting by distance requires near and radius_km")
+		}
+	}
+	filter.Sort = sort
+
+	return filter, nil
+}
+
+// parseNumber parses a finite number. strconv.ParseFloat also accepts NaN
+// and Inf, which slip through range checks and mean nothing to SQL.
+func parseNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return number, nil
+}
+
+// parseCoordinates parses n comma-separated numbers. Pairs are read as
+// latitude,longitude for n == 2 and as longitude,latitude otherwise, the
+// GeoJSON bbox order, and checked against their ranges.
+func parseCoordinates(value string, n int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d numbers", n)
+	}
+
+	coords := make([]float64, n)
+	for i, part := range parts {
+		coord, err := parseNumber(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", part)
+		}
+		isLatitude := (n == 2) == (i%2 == 0)
+		if isLatitude && (coord < -90 || coord > 90) {
+			return nil, fmt.Errorf("latitude %v is out of range", coord)
+		}
+		if !isLatitude && (coord < -180 || coord > 180) {
+			return nil, fmt.Errorf("longitude %v is out of range", coord)
+		}
+		coords[i] = coord
+	}
+	return coords, nil
+}
+
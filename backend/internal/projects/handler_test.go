@@ -0,0 +1,68 @@
+package projects
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		value   string
+		n       int
+		wantErr bool
+	}{
+		{"12.5,77.25", 2, false},
+		{" -90 , 180 ", 2, false},
+		{"91,0", 2, true},
+		{"0,181", 2, true},
+		{"NaN,0", 2, true},
+		{"0,Inf", 2, true},
+		{"1,2,3", 2, true},
+		{"-180,-90,180,90", 4, false},
+		{"0,-91,1,1", 4, true},
+		{"0,0,+Inf,1", 4, true},
+		{"a,b,c,d", 4, true},
+	}
+
+	for _, tt := range tests {
+		_, err := parseCoordinates(tt.value, tt.n)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCoordinates(%q, %d) error = %v, wantErr %v", tt.value, tt.n, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseListFilterRejectsNonFiniteNumbers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []string{
+		"budget_min=NaN",
+		"budget_max=Inf",
+		"budget_max=-Infinity",
+		"near=12,77&radius_km=NaN",
+		"near=12,77&radius_km=Inf",
+		"near=NaN,77&radius_km=5",
+		"bbox=0,0,NaN,1",
+	}
+
+	for _, query := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/projects?"+query, nil)
+		if _, err := parseListFilter(c); err == nil {
+			t.Errorf("parseListFilter(%q) succeeded, want error", query)
+		}
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/projects?budget_min=10.5&near=12,77&radius_km=5", nil)
+	filter, err := parseListFilter(c)
+	if err != nil {
+		t.Fatalf("parseListFilter: %v", err)
+	}
+	if filter.BudgetMin == nil || *filter.BudgetMin != 10.5 || filter.Near == nil || filter.Near.RadiusKm != 5 {
+		t.Errorf("parseListFilter = %+v", filter)
+	}
+}
+
@@ -0,0 +1,342 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrMemberNotFound  = errors.New("project member not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrMemberExists    = errors.New("user is already a member of this project")
+	ErrMemberLimit     = errors.New("project member limit reached for this role")
+	ErrInvalidRole     = errors.New("invalid project role")
+	ErrSelfGrant       = errors.New("cannot change your own project membership")
+)
+
+const memberColumns = `pm.id, pm.project_id, pm.user_id, u.username, u.email, pm.role,
+		       pm.added_by, pm.created_at, pm.updated_at`
+
+func scanMember(row pgx.Row) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := row.Scan(&member.ID, &member.ProjectID, &member.UserID, &member.Username, &member.Email,
+		&member.Role, &member.AddedBy, &member.CreatedAt, &member.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// lockProject locks the project row for the rest of the transaction so that
+// concurrent membership changes are serialized and the caps hold.
+func lockProject(ctx context.Context, tx pgx.Tx, projectID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, "SELECT id FROM projects WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", projectID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrProjectNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+	return nil
+}
+
+// checkRoleCapacity returns ErrMemberLimit if the project already has the
+// maximum number of members with the given role.
+func checkRoleCapacity(ctx context.Context, tx pgx.Tx, projectID uuid.UUID, role models.ProjectRole) error {
+	var count int
+	err := tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM project_members WHERE project_id = $1 AND role = $2",
+		projectID, role).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to count project members: %w", err)
+	}
+	if count >= role.Limit() {
+		return fmt.Errorf("%w: at most %d %s members", ErrMemberLimit, role.Limit(), role)
+	}
+	return nil
+}
+
+// checkRoleGrant guards membership changes against privilege escalation.
+// Nobody may change their own membership, and granting, changing or
+// removing the admin or sub-admin role needs write access to the project's
+// organization, since those roles can modify the project. roles holds the
+// member's current role, if any, and the role granted.
+func (s *Service) checkRoleGrant(ctx context.Context, tx pgx.Tx, actor *models.User, projectID, userID uuid.UUID, roles ...models.ProjectRole) error {
+	if userID == actor.ID {
+		return ErrSelfGrant
+	}
+	privileged := false
+	for _, role := range roles {
+		privileged = privileged || role.CanModifyProject()
+	}
+	if !privileged {
+		return nil
+	}
+	if actor.CanAccessAllOrganizations() {
+		return nil
+	}
+
+	var orgID *uuid.UUID
+	if err := tx.QueryRow(ctx, "SELECT organization_id FROM projects WHERE id = $1", projectID).Scan(&orgID); err != nil {
+		return fmt.Errorf("failed to get project organization: %w", err)
+	}
+	if orgID == nil {
+		return ErrNoWriteAccess
+	}
+	return s.checkOrganizationWrite(ctx, actor, *orgID)
+}
+
+func (s *Service) ListMembers(ctx context.Context, projectID uuid.UUID, role *models.ProjectRole) ([]*models.ProjectMember, error) {
+	var exists bool
+	if err := s.db.Pool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL)", projectID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check project: %w", err)
+	}
+	if !exists {
+		return nil, ErrProjectNotFound
+	}
+
+	query := `
+		SELECT ` + memberColumns + `
+		FROM project_members pm
+		JOIN users u ON u.id = pm.user_id
+		WHERE pm.project_id = $1`
+	args := []interface{}{projectID}
+	if role != nil {
+		query += " AND pm.role = $2"
+		args = append(args, *role)
+	}
+	query += " ORDER BY pm.role, u.username"
+
+	rows, err := s.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*models.ProjectMember{}
+	for rows.Next() {
+		member, err := scanMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// AddMember adds an existing user to the project. The role caps are checked
+// while the project row is locked, so concurrent additions cannot exceed them.
+func (s *Service) AddMember(ctx context.Context, actor *models.User, projectID uuid.UUID, req *models.AddProjectMemberRequest) (*models.ProjectMember, error) {
+	if !req.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+
+	var userID uuid.UUID
+	err = tx.QueryRow(ctx,
+		"SELECT id FROM users WHERE username = $1 AND is_active", req.Username).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := s.checkRoleGrant(ctx, tx, actor, projectID, userID, req.Role); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM project_members WHERE project_id = $1 AND user_id = $2)",
+		projectID, userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check project membership: %w", err)
+	}
+	if exists {
+		return nil, ErrMemberExists
+	}
+
+	if err := checkRoleCapacity(ctx, tx, projectID, req.Role); err != nil {
+		return nil, err
+	}
+
+	var memberID uuid.UUID
+	now := time.Now()
+	err = tx.QueryRow(ctx, `
+		INSERT INTO project_members (project_id, user_id, role, added_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id`,
+		projectID, userID, req.Role, actor.ID, now).Scan(&memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add project member: %w", err)
+	}
+
+	member, err := scanMember(tx.QueryRow(ctx, `
+		SELECT `+memberColumns+`
+		FROM project_members pm
+		JOIN users u ON u.id = pm.user_id
+		WHERE pm.id = $1`, memberID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit project member: %w", err)
+	}
+
+	s.logger.Info("Project member added",
+		zap.String("project_id", projectID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("role", string(req.Role)),
+		zap.String("added_by", actor.ID.String()))
+
+	return member, nil
+}
+
+// UpdateMemberRole changes a member's role, checking the cap of the new role.
+func (s *Service) UpdateMemberRole(ctx context.Context, actor *models.User, projectID, userID uuid.UUID, req *models.UpdateProjectMemberRequest) (*models.ProjectMember, error) {
+	if !req.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+
+	member, err := scanMember(tx.QueryRow(ctx, `
+		SELECT `+memberColumns+`
+		FROM project_members pm
+		JOIN users u ON u.id = pm.user_id
+		WHERE pm.project_id = $1 AND pm.user_id = $2`, projectID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project member: %w", err)
+	}
+
+	if member.Role == req.Role {
+		return member, nil
+	}
+
+	if err := s.checkRoleGrant(ctx, tx, actor, projectID, userID, member.Role, req.Role); err != nil {
+		return nil, err
+	}
+
+	if err := checkRoleCapacity(ctx, tx, projectID, req.Role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx,
+		"UPDATE project_members SET role = $2, updated_at = $3 WHERE id = $1",
+		member.ID, req.Role, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit project member: %w", err)
+	}
+
+	s.logger.Info("Project member role changed",
+		zap.String("project_id", projectID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("from_role", string(member.Role)),
+		zap.String("to_role", string(req.Role)),
+		zap.String("updated_by", actor.ID.String()))
+
+	member.Role = req.Role
+	member.UpdatedAt = now
+	return member, nil
+}
+
+// RemoveMember removes a user from the project. Like role changes, this
+// runs with the project row locked and is subject to checkRoleGrant.
+func (s *Service) RemoveMember(ctx context.Context, actor *models.User, projectID, userID uuid.UUID) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockProject(ctx, tx, projectID); err != nil {
+		return err
+	}
+
+	var role models.ProjectRole
+	err = tx.QueryRow(ctx,
+		"SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2",
+		projectID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load project member: %w", err)
+	}
+
+	if err := s.checkRoleGrant(ctx, tx, actor, projectID, userID, role); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM project_members WHERE project_id = $1 AND user_id = $2",
+		projectID, userID); err != nil {
+		return fmt.Errorf("failed to remove project member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit project member removal: %w", err)
+	}
+
+	s.logger.Info("Project member removed",
+		zap.String("project_id", projectID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("role", string(role)),
+		zap.String("removed_by", actor.ID.String()))
+	return nil
+}
+
+// GetMemberRole returns the user's role on the project, or "" if the user is
+// not a member.
+func (s *Service) GetMemberRole(ctx context.Context, projectID, userID uuid.UUID) (string, error) {
+	var role string
+	err := s.db.Pool.QueryRow(ctx,
+		"SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2",
+		projectID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get project role: %w", err)
+	}
+	return role, nil
+}
+
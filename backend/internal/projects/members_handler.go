@@ -0,0 +1,214 @@
+package projects
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Summary List project members
+// @Description Get the users assigned to a project and their project roles
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param role query string false "Filter by project role (admin, subadmin, user)"
+// @Success 200 {array} models.ProjectMember
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/users [get]
+func (h *Handler) ListMembers(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	if _, ok := h.requireProjectAccess(c, projectID); !ok {
+		return
+	}
+
+	var role *models.ProjectRole
+	if roleStr := c.Query("role"); roleStr != "" {
+		r := models.ProjectRole(roleStr)
+		if !r.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+			return
+		}
+		role = &r
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), projectID, role)
+	if err != nil {
+		h.respondMemberError(c, "Failed to list project members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Add a project member
+// @Description Assign a user to a project. A project has at most 4 admins, 10 sub-admins and 100 users. Granting admin or sub-admin needs write access to the project's organization, and you cannot add yourself.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param request body models.AddProjectMemberRequest true "Member data"
+// @Success 201 {object} models.ProjectMember
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /projects/{id}/users [post]
+func (h *Handler) AddMember(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	user, ok := h.requireProjectAccess(c, projectID)
+	if !ok {
+		return
+	}
+
+	var req models.AddProjectMemberRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service.AddMember(c.Request.Context(), user, projectID, &req)
+	if err != nil {
+		h.respondMemberError(c, "Failed to add project member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// @Summary Update a project member
+// @Description Change a member's role on a project, subject to the role caps. Granting admin or sub-admin needs write access to the project's organization, and you cannot change your own role.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param userId path string true "User ID"
+// @Param request body models.UpdateProjectMemberRequest true "New role"
+// @Success 200 {object} models.ProjectMember
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /projects/{id}/users/{userId} [put]
+func (h *Handler) UpdateMember(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, ok := h.requireProjectAccess(c, projectID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateProjectMemberRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(c.Request.Context(), user, projectID, userID, &req)
+	if err != nil {
+		h.respondMemberError(c, "Failed to update project member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Remove a project member
+// @Description Remove a user from a project
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param userId path string true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/users/{userId} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, ok := h.requireProjectAccess(c, projectID)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), user, projectID, userID); err != nil {
+		h.respondMemberError(c, "Failed to remove project member", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireProjectAccess responds with 404 unless the project is visible to
+// the current user, so that members of other organizations' projects can't
+// be listed or changed.
+func (h *Handler) requireProjectAccess(c *gin.Context, projectID uuid.UUID) (*models.User, bool) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	if _, err := h.service.GetProject(c.Request.Context(), user, projectID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (h *Handler) respondMemberError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: ErrMemberNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrUserNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrInvalidRole, Status: http.StatusBadRequest},
+		api.ErrorResponse{Err: ErrSelfGrant, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrNoWriteAccess, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrMemberExists, Status: http.StatusConflict},
+		api.ErrorResponse{Err: ErrMemberLimit, Status: http.StatusConflict},
+	)
+}
+
@@ -0,0 +1,69 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// orphanProjectTx answers the organization lookup for a project that
+// belongs to no organization. Any other call panics on the nil pgx.Tx.
+type orphanProjectTx struct {
+	pgx.Tx
+	queried bool
+}
+
+type nullOrgRow struct{}
+
+func (nullOrgRow) Scan(dest ...interface{}) error {
+	*dest[0].(**uuid.UUID) = nil
+	return nil
+}
+
+func (tx *orphanProjectTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	tx.queried = true
+	return nullOrgRow{}
+}
+
+func TestCheckRoleGrant(t *testing.T) {
+	s := &Service{}
+	localadmin := &models.User{ID: uuid.New(), Role: models.RoleLocaladmin}
+	sysadmin := &models.User{ID: uuid.New(), Role: models.RoleSysadmin}
+	member := uuid.New()
+
+	tests := []struct {
+		name      string
+		actor     *models.User
+		userID    uuid.UUID
+		roles     []models.ProjectRole
+		wantErr   error
+		wantQuery bool
+	}{
+		{"own membership", sysadmin, sysadmin.ID, []models.ProjectRole{models.ProjectRoleUser}, ErrSelfGrant, false},
+		{"plain role", localadmin, member, []models.ProjectRole{models.ProjectRoleUser}, nil, false},
+		{"plain role change", localadmin, member, []models.ProjectRole{models.ProjectRoleUser, models.ProjectRoleUser}, nil, false},
+		{"grant admin", localadmin, member, []models.ProjectRole{models.ProjectRoleUser, models.ProjectRoleAdmin}, ErrNoWriteAccess, true},
+		{"demote admin", localadmin, member, []models.ProjectRole{models.ProjectRoleAdmin, models.ProjectRoleUser}, ErrNoWriteAccess, true},
+		{"remove sub-admin", localadmin, member, []models.ProjectRole{models.ProjectRoleSubAdmin}, ErrNoWriteAccess, true},
+		{"sysadmin grants admin", sysadmin, member, []models.ProjectRole{models.ProjectRoleUser, models.ProjectRoleAdmin}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &orphanProjectTx{}
+			err := s.checkRoleGrant(context.Background(), tx, tt.actor, uuid.New(), tt.userID, tt.roles...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkRoleGrant() error = %v, want %v", err, tt.wantErr)
+			}
+			if tx.queried != tt.wantQuery {
+				t.Errorf("looked up organization = %v, want %v", tx.queried, tt.wantQuery)
+			}
+		})
+	}
+}
+
@@ -0,0 +1,331 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"project-management-backend/internal/jsonschema"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMetadataSchemaNotFound = errors.New("metadata schema not found")
+	// ErrInvalidFilter is returned for list filters that don't fit the
+	// metadata schemas
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Metadata field types that filters compare by value. Other fields compare
+// as text.
+const (
+	fieldNumber  = "number"
+	fieldBoolean = "boolean"
+	fieldDate    = "date"
+	fieldText    = "string"
+)
+
+// querier is satisfied by the pool and by transactions
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+const metadataSchemaColumns = `project_type, schema, updated_by, created_at, updated_at`
+
+func scanMetadataSchema(row pgx.Row) (*models.MetadataSchema, error) {
+	var schema models.MetadataSchema
+	var document []byte
+	err := row.Scan(&schema.ProjectType, &document, &schema.UpdatedBy, &schema.CreatedAt, &schema.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = document
+
+	compiled, err := jsonschema.Compile(document)
+	if err != nil {
+		return nil, fmt.Errorf("stored metadata schema for %s: %w", schema.ProjectType, err)
+	}
+	schema.Fields = metadataFields(compiled)
+	return &schema, nil
+}
+
+func (s *Service) ListMetadataSchemas(ctx context.Context) ([]*models.MetadataSchema, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+metadataSchemaColumns+`
+		FROM project_metadata_schemas
+		ORDER BY project_type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metadata schemas: %w", err)
+	}
+	defer rows.Close()
+
+	schemas := []*models.MetadataSchema{}
+	for rows.Next() {
+		schema, err := scanMetadataSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metadata schema: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, rows.Err()
+}
+
+func (s *Service) GetMetadataSchema(ctx context.Context, projectType models.ProjectType) (*models.MetadataSchema, error) {
+	schema, err := scanMetadataSchema(s.db.Pool.QueryRow(ctx, `
+		SELECT `+metadataSchemaColumns+`
+		FROM project_metadata_schemas
+		WHERE project_type = $1`, projectType))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMetadataSchemaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata schema: %w", err)
+	}
+	return schema, nil
+}
+
+// SetMetadataSchema registers or replaces the schema for a project type.
+// The root of the schema must describe an object. Invalid schemas are
+// returned as validation.Errors.
+func (s *Service) SetMetadataSchema(ctx context.Context, user *models.User, projectType models.ProjectType, document []byte) (*models.MetadataSchema, error) {
+	compiled, err := jsonschema.Compile(document)
+	if err != nil {
+		return nil, validation.Errors{{Field: "schema", Rule: "schema", Message: err.Error()}}
+	}
+	if len(compiled.Types) != 1 || compiled.Types[0] != "object" {
+		return nil, validation.Errors{{Field: "schema.type", Rule: "schema", Message: `must be "object"`}}
+	}
+
+	schema, err := scanMetadataSchema(s.db.Pool.QueryRow(ctx, `
+		INSERT INTO project_metadata_schemas (project_type, schema, updated_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_type) DO UPDATE
+		SET schema = EXCLUDED.schema, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING `+metadataSchemaColumns,
+		projectType, document, user.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save metadata schema: %w", err)
+	}
+
+	s.logger.Info("Metadata schema set",
+		zap.String("project_type", string(projectType)),
+		zap.String("updated_by", user.ID.String()))
+	return schema, nil
+}
+
+func (s *Service) DeleteMetadataSchema(ctx context.Context, user *models.User, projectType models.ProjectType) error {
+	result, err := s.db.Pool.Exec(ctx, "DELETE FROM project_metadata_schemas WHERE project_type = $1", projectType)
+	if err != nil {
+		return fmt.Errorf("failed to delete metadata schema: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrMetadataSchemaNotFound
+	}
+
+	s.logger.Info("Metadata schema deleted",
+		zap.String("project_type", string(projectType)),
+		zap.String("deleted_by", user.ID.String()))
+	return nil
+}
+
+// checkMetadata validates the project's metadata against the schema of its
+// type, if one is registered. Missing metadata is checked as an empty
+// object, so required fields are enforced.
+func checkMetadata(ctx context.Context, q querier, project *models.Project) error {
+	if project.Type == nil {
+		return nil
+	}
+
+	var document []byte
+	err := q.QueryRow(ctx,
+		"SELECT schema FROM project_metadata_schemas WHERE project_type = $1", *project.Type).Scan(&document)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get metadata schema: %w", err)
+	}
+
+	schema, err := jsonschema.Compile(document)
+	if err != nil {
+		return fmt.Errorf("stored metadata schema for %s: %w", *project.Type, err)
+	}
+
+	// Round-trip through JSON so the values have the types encoding/json
+	// produces, whatever built the map
+	encoded, err := json.Marshal(project.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	metadata := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &metadata); err != nil && string(encoded) != "null" {
+		return fmt.Errorf("failed to decode metadata: %w", err)
+	}
+
+	var fieldErrs validation.Errors
+	for _, schemaErr := range schema.Validate(metadata) {
+		field := "metadata"
+		if schemaErr.Path != "" {
+			field += "." + schemaErr.Path
+		}
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: field, Rule: schemaErr.Keyword, Message: schemaErr.Message})
+	}
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+	return nil
+}
+
+// metadataJSON encodes the metadata for noticing changes. Map keys are
+// sorted, so equal metadata encodes the same.
+func metadataJSON(project *models.Project) string {
+	encoded, _ := json.Marshal(project.Metadata)
+	return string(encoded)
+}
+
+// metadataFields lists the top-level scalar properties of a schema, which
+// are the ones ListProjects filters by value
+func metadataFields(schema *jsonschema.Schema) []models.MetadataField {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []models.MetadataField{}
+	for _, name := range names {
+		property := schema.Properties[name]
+		fieldType := scalarType(property)
+		if fieldType == "" {
+			continue
+		}
+		fields = append(fields, models.MetadataField{
+			Name:   name,
+			Type:   fieldType,
+			Format: property.Format,
+			Range:  fieldType == fieldNumber || fieldType == fieldDate,
+		})
+	}
+	return fields
+}
+
+// scalarType is the filter type of a property, or "" if it is not a
+// single scalar type
+func scalarType(property *jsonschema.Schema) string {
+	if len(property.Types) != 1 {
+		return ""
+	}
+	switch property.Types[0] {
+	case "number", "integer":
+		return fieldNumber
+	case "boolean":
+		return fieldBoolean
+	case "string":
+		if property.Format == "date" {
+			return fieldDate
+		}
+		return fieldText
+	default:
+		return ""
+	}
+}
+
+// resolveMetadataFilter looks up the schema types of the metadata fields
+// the filter uses, so they compare by value, and checks the filter values.
+// Only the schemas of the filtered project types are considered. A field
+// whose type differs between schemas compares as text and has no range
+// filters.
+func (s *Service) resolveMetadataFilter(ctx context.Context, filter *ListProjectsFilter) error {
+	if len(filter.Metadata) == 0 && len(filter.MetadataMin) == 0 && len(filter.MetadataMax) == 0 {
+		return nil
+	}
+
+	query := "SELECT " + metadataSchemaColumns + " FROM project_metadata_schemas"
+	var args []interface{}
+	if len(filter.Types) > 0 {
+		query += " WHERE project_type = ANY($1)"
+		args = append(args, filter.Types)
+	}
+	rows, err := s.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata schemas: %w", err)
+	}
+	defer rows.Close()
+
+	fields := map[string]models.MetadataField{}
+	for rows.Next() {
+		schema, err := scanMetadataSchema(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan metadata schema: %w", err)
+		}
+		for _, field := range schema.Fields {
+			if existing, ok := fields[field.Name]; ok && existing.Type != field.Type {
+				field = models.MetadataField{Name: field.Name, Type: fieldText}
+			}
+			fields[field.Name] = field
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get metadata schemas: %w", err)
+	}
+
+	for key, value := range filter.Metadata {
+		if field, ok := fields[key]; ok {
+			normalized, err := fieldValue(field, value)
+			if err != nil {
+				return fmt.Errorf("%w: metadata[%s] %v", ErrInvalidFilter, key, err)
+			}
+			filter.Metadata[key] = normalized
+		}
+	}
+	for param, values := range map[string]map[string]string{"metadata_min": filter.MetadataMin, "metadata_max": filter.MetadataMax} {
+		for key, value := range values {
+			field, ok := fields[key]
+			if !ok || !field.Range {
+				return fmt.Errorf("%w: %s[%s] needs a number or date field defined by the metadata schemas", ErrInvalidFilter, param, key)
+			}
+			normalized, err := fieldValue(field, value)
+			if err != nil {
+				return fmt.Errorf("%w: %s[%s] %v", ErrInvalidFilter, param, key, err)
+			}
+			values[key] = normalized
+		}
+	}
+
+	filter.metadataFields = fields
+	return nil
+}
+
+// fieldValue checks a filter value against the field type and returns it
+// in the form the SQL casts accept
+func fieldValue(field models.MetadataField, value string) (string, error) {
+	switch field.Type {
+	case fieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", errors.New("must be a number")
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case fieldBoolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", errors.New("must be true or false")
+		}
+		return strconv.FormatBool(b), nil
+	case fieldDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", errors.New("must be a date (YYYY-MM-DD)")
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,143 @@
+package projects
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSchemaSize limits the size of a metadata schema document
+const maxSchemaSize = 256 << 10
+
+// @Summary List metadata schemas
+// @Description Get the JSON Schemas registered for project metadata, with the fields each makes filterable
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.MetadataSchema
+// @Failure 401 {object} map[string]string
+// @Router /projects/metadata-schemas [get]
+func (h *Handler) ListMetadataSchemas(c *gin.Context) {
+	schemas, err := h.service.ListMetadataSchemas(c.Request.Context())
+	if err != nil {
+		h.respondSchemaError(c, "Failed to list metadata schemas", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schemas)
+}
+
+// @Summary Get a metadata schema
+// @Description Get the JSON Schema for the metadata of projects of a type
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Project type"
+// @Success 200 {object} models.MetadataSchema
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/metadata-schemas/{type} [get]
+func (h *Handler) GetMetadataSchema(c *gin.Context) {
+	projectType, ok := parseProjectType(c)
+	if !ok {
+		return
+	}
+
+	schema, err := h.service.GetMetadataSchema(c.Request.Context(), projectType)
+	if err != nil {
+		h.respondSchemaError(c, "Failed to get metadata schema", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// @Summary Set a metadata schema
+// @Description Register or replace the JSON Schema that the metadata of projects of a type must satisfy. The body is the schema itself and must describe an object. Supported keywords: type, properties, required, additionalProperties, items, enum, const, minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength, pattern, format (date, date-time, email, uri), minItems, maxItems, uniqueItems. Projects are checked when their metadata or type next changes.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Project type"
+// @Param schema body object true "JSON Schema"
+// @Success 200 {object} models.MetadataSchema
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]interface{}
+// @Router /projects/metadata-schemas/{type} [put]
+func (h *Handler) SetMetadataSchema(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectType, ok := parseProjectType(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSchemaSize)
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Schema must be at most 256 KiB"})
+		return
+	}
+
+	schema, err := h.service.SetMetadataSchema(c.Request.Context(), user, projectType, document)
+	if err != nil {
+		h.respondSchemaError(c, "Failed to set metadata schema", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// @Summary Delete a metadata schema
+// @Description Stop validating the metadata of projects of a type
+// @Tags projects
+// @Security BearerAuth
+// @Param type path string true "Project type"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/metadata-schemas/{type} [delete]
+func (h *Handler) DeleteMetadataSchema(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	projectType, ok := parseProjectType(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteMetadataSchema(c.Request.Context(), user, projectType); err != nil {
+		h.respondSchemaError(c, "Failed to delete metadata schema", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondSchemaError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrMetadataSchemaNotFound, Status: http.StatusNotFound, Message: "Metadata schema not found"},
+	)
+}
+
+func parseProjectType(c *gin.Context) (models.ProjectType, bool) {
+	projectType := models.ProjectType(c.Param("type"))
+	if !projectType.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project type"})
+		return "", false
+	}
+	return projectType, true
+}
@@ -0,0 +1,147 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOwnerNotFound      = errors.New("owner not found")
+	ErrInvalidOwnerShares = errors.New("project owners must be 1 to 10 owners whose percentages add up to 100")
+)
+
+// ownerSharesConstraint is raised by the deferred trigger on project_owners
+// when a transaction commits an invalid set of shares.
+const ownerSharesConstraint = "project_owners_shares"
+
+func (s *Service) ListOwners(ctx context.Context, user *models.User, projectID uuid.UUID) ([]*models.ProjectOwner, error) {
+	if _, err := s.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT po.project_id, po.owner_id, po.percentage, po.role, po.created_at, po.updated_at,
+		       o.id, o.name, o.email, o.phone, o.metadata, o.created_at, o.updated_at
+		FROM project_owners po
+		JOIN owners o ON o.id = po.owner_id
+		WHERE po.project_id = $1
+		ORDER BY po.role = 'primary' DESC, po.percentage DESC, o.name`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := []*models.ProjectOwner{}
+	for rows.Next() {
+		var share models.ProjectOwner
+		var owner models.Owner
+		if err := rows.Scan(&share.ProjectID, &share.OwnerID, &share.Percentage, &share.Role,
+			&share.CreatedAt, &share.UpdatedAt, &owner.ID, &owner.Name, &owner.Email, &owner.Phone,
+			&owner.Metadata, &owner.CreatedAt, &owner.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project owner: %w", err)
+		}
+		share.Owner = &owner
+		owners = append(owners, &share)
+	}
+
+	return owners, rows.Err()
+}
+
+// SetOwners replaces all owners of a project in one transaction. The shares
+// are checked again by the database at commit, so a concurrent reassignment
+// can never leave the project with a total other than 100.
+func (s *Service) SetOwners(ctx context.Context, user *models.User, projectID uuid.UUID, req *models.SetProjectOwnersRequest) ([]*models.ProjectOwner, error) {
+	project, err := s.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM project_owners WHERE project_id = $1", projectID); err != nil {
+		return nil, fmt.Errorf("failed to clear project owners: %w", err)
+	}
+	if err := insertOwners(ctx, tx, projectID, req.Owners); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, ownerSharesError(err, "failed to commit project owners")
+	}
+
+	s.logger.Info("Project owners updated",
+		zap.String("project_id", projectID.String()),
+		zap.Int("owners", len(req.Owners)),
+		zap.String("updated_by", user.ID.String()))
+
+	return s.ListOwners(ctx, user, projectID)
+}
+
+// insertOwners adds the shares to the project. The owners are locked so they
+// can't be removed from the registry before the transaction commits.
+func insertOwners(ctx context.Context, tx pgx.Tx, projectID uuid.UUID, shares []models.OwnerShareInput) error {
+	ids := make([]uuid.UUID, len(shares))
+	for i, share := range shares {
+		ids[i] = share.OwnerID
+	}
+
+	var found int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM owners WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE
+		) locked`, ids).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to check owners: %w", err)
+	}
+	if found != len(ids) {
+		return ErrOwnerNotFound
+	}
+
+	now := time.Now()
+	for _, share := range shares {
+		role := share.Role
+		if role == "" {
+			role = models.OwnerRoleCoOwner
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO project_owners (project_id, owner_id, percentage, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)`,
+			projectID, share.OwnerID, share.Percentage, role, now)
+		if err != nil {
+			return ownerSharesError(err, "failed to add project owner")
+		}
+	}
+
+	return nil
+}
+
+// ownerSharesError maps a violation of the share rules to
+// ErrInvalidOwnerShares and wraps anything else.
+func ownerSharesError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == ownerSharesConstraint {
+		return fmt.Errorf("%w: %s", ErrInvalidOwnerShares, pgErr.Message)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
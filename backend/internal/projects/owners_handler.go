@@ -0,0 +1,97 @@
+package projects
+
+import (
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary List project owners
+// @Description Get the owners of a project with their percentage shares
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} models.ProjectOwner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/owners [get]
+func (h *Handler) GetProjectOwners(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	owners, err := h.service.ListOwners(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondOwnerError(c, "Failed to list project owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owners)
+}
+
+// @Summary Replace project owners
+// @Description Reassign a project to 1-10 owners whose percentages add up to 100. The previous owners are replaced atomically.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param request body models.SetProjectOwnersRequest true "New owners and their shares"
+// @Success 200 {array} models.ProjectOwner
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id}/owners [put]
+func (h *Handler) SetProjectOwners(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req models.SetProjectOwnersRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	owners, err := h.service.SetOwners(c.Request.Context(), user, id, &req)
+	if err != nil {
+		h.respondOwnerError(c, "Failed to update project owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, owners)
+}
+
+func (h *Handler) respondOwnerError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: ErrOwnerNotFound, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrInvalidOwnerShares, Status: http.StatusUnprocessableEntity},
+		api.ErrorResponse{Err: ErrNoWriteAccess, Status: http.StatusForbidden},
+	)
+}
+
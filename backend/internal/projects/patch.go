@@ -0,0 +1,180 @@
+package projects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-backend/internal/jsonpatch"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnsupportedPatch = errors.New("unsupported patch content type")
+	ErrInvalidProject   = errors.New("invalid project")
+)
+
+const dateLayout = "2006-01-02"
+
+// patchDocument is the part of a project a PATCH can change. Every member
+// is always present, so JSON Patch paths resolve and null clears a field.
+// It is also used to validate the result of every update.
+type patchDocument struct {
+	Name       *string                `json:"name" validate:"required,notblank,max=255"`
+	Address    *string                `json:"address" validate:"omitempty,max=255"`
+	City       *string                `json:"city" validate:"omitempty,max=100"`
+	State      *string                `json:"state" validate:"omitempty,max=100"`
+	PostalCode *string                `json:"postal_code" validate:"omitempty,max=20"`
+	Type       *string                `json:"type" validate:"omitempty,oneof=Colony IndependentHouse LinkHouse HousingEstate"`
+	Latitude   *float64               `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64               `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	OwnerName  *string                `json:"owner_name" validate:"omitempty,max=255"`
+	Status     *string                `json:"status" validate:"omitempty,oneof=planning active completed on-hold cancelled"`
+	Budget     *float64               `json:"budget" validate:"omitempty,min=0"`
+	StartDate  *string                `json:"start_date"`
+	EndDate    *string                `json:"end_date"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Documents  map[string]interface{} `json:"documents"`
+}
+
+func newPatchDocument(project *models.Project) *patchDocument {
+	doc := &patchDocument{
+		Name:       &project.Name,
+		Address:    project.Address,
+		City:       project.City,
+		State:      project.State,
+		PostalCode: project.PostalCode,
+		Type:       (*string)(project.Type),
+		Latitude:   project.Latitude,
+		Longitude:  project.Longitude,
+		OwnerName:  project.OwnerName,
+		Status:     project.Status,
+		Budget:     project.Budget,
+		StartDate:  dateString(project.StartDate),
+		EndDate:    dateString(project.EndDate),
+		Metadata:   project.Metadata,
+		Documents:  project.Documents,
+	}
+	if doc.Metadata == nil {
+		doc.Metadata = map[string]interface{}{}
+	}
+	if doc.Documents == nil {
+		doc.Documents = map[string]interface{}{}
+	}
+	return doc
+}
+
+// applyTo validates the patched document and copies it onto the project.
+// Validation failures are returned as validation.Errors.
+func (d *patchDocument) applyTo(project *models.Project) error {
+	var fieldErrs validation.Errors
+	if err := validation.Struct(d); err != nil {
+		if !errors.As(err, &fieldErrs) {
+			return err
+		}
+	}
+
+	startDate, ok := parseDate(d.StartDate)
+	if !ok {
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: "start_date", Rule: "date", Message: "must be a date (YYYY-MM-DD)"})
+	}
+	endDate, ok := parseDate(d.EndDate)
+	if !ok {
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: "end_date", Rule: "date", Message: "must be a date (YYYY-MM-DD)"})
+	}
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: "end_date", Rule: "gtefield", Message: "must not be before start_date"})
+	}
+	if d.Latitude != nil && d.Longitude == nil {
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: "longitude", Rule: "required_with", Message: "is required with latitude"})
+	}
+	if d.Longitude != nil && d.Latitude == nil {
+		fieldErrs = append(fieldErrs, validation.FieldError{Field: "latitude", Rule: "required_with", Message: "is required with longitude"})
+	}
+
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+
+	project.Name = *d.Name
+	project.Address = d.Address
+	project.City = d.City
+	project.State = d.State
+	project.PostalCode = d.PostalCode
+	project.Type = (*models.ProjectType)(d.Type)
+	project.Latitude = d.Latitude
+	project.Longitude = d.Longitude
+	project.OwnerName = d.OwnerName
+	project.Status = d.Status
+	project.Budget = d.Budget
+	project.StartDate = startDate
+	project.EndDate = endDate
+	project.Metadata = models.JSONB(d.Metadata)
+	project.Documents = models.JSONB(d.Documents)
+	return nil
+}
+
+// parseDate accepts a plain date or a full RFC 3339 timestamp, as returned
+// by the project endpoints
+func parseDate(value *string) (*time.Time, bool) {
+	if value == nil {
+		return nil, true
+	}
+	for _, layout := range []string{dateLayout, time.RFC3339} {
+		if date, err := time.Parse(layout, *value); err == nil {
+			return &date, true
+		}
+	}
+	return nil, false
+}
+
+func dateString(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format(dateLayout)
+	return &formatted
+}
+
+// PatchProject applies a JSON Merge Patch or JSON Patch, chosen by
+// contentType, to the project and saves the validated result as a new
+// version. expectedVersions works as in UpdateProject.
+func (s *Service) PatchProject(ctx context.Context, user *models.User, id uuid.UUID, contentType string, patch []byte, expectedVersions []int) (*models.Project, error) {
+	var applyPatch func(doc, patch []byte) ([]byte, error)
+	switch contentType {
+	case jsonpatch.MergePatchContentType:
+		applyPatch = jsonpatch.MergePatch
+	case jsonpatch.JSONPatchContentType:
+		applyPatch = jsonpatch.Apply
+	default:
+		return nil, ErrUnsupportedPatch
+	}
+
+	return s.updateProject(ctx, user, id, expectedVersions, func(project *models.Project) error {
+		doc, err := json.Marshal(newPatchDocument(project))
+		if err != nil {
+			return fmt.Errorf("failed to encode project: %w", err)
+		}
+
+		patched, err := applyPatch(doc, patch)
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(patched))
+		decoder.DisallowUnknownFields()
+		var result patchDocument
+		if err := decoder.Decode(&result); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProject, err)
+		}
+
+		return result.applyTo(project)
+	})
+}
+
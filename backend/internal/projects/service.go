@@ -2,91 +2,255 @@ package projects
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"project-management-backend/internal/db"
 	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrOrganizationRequired = errors.New("organization_id is required")
+	ErrNoWriteAccess        = errors.New("no write access to this project's organization")
+	ErrVersionMismatch      = errors.New("project has been modified since it was read")
+)
+
+const projectColumns = `id, name, address, city, state, postal_code, owner_name, status,
+		       budget, start_date, end_date, metadata, documents, created_at, updated_at,
+		       organization_id, version, updated_by, deleted_at, deleted_by, type, latitude, longitude`
+
+func scanProject(row pgx.Row) (*models.Project, error) {
+	var project models.Project
+	err := row.Scan(
+		&project.ID, &project.Name, &project.Address, &project.City, &project.State,
+		&project.PostalCode, &project.OwnerName, &project.Status, &project.Budget,
+		&project.StartDate, &project.EndDate, &project.Metadata, &project.Documents,
+		&project.CreatedAt, &project.UpdatedAt, &project.OrganizationID, &project.Version,
+		&project.UpdatedBy, &project.DeletedAt, &project.DeletedBy, &project.Type, &project.Latitude,
+		&project.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
 type Service struct {
-	db     *db.Database
-	logger *zap.Logger
+	db       *db.Database
+	logger   *zap.Logger
+	workflow *Workflow
 }
 
 func NewService(database *db.Database, logger *zap.Logger) *Service {
 	return &Service{
-		db:     database,
-		logger: logger,
+		db:       database,
+		logger:   logger,
+		workflow: DefaultWorkflow(),
+	}
+}
+
+// scopeFilter restricts a projects query to the organizations the user
+// belongs to and the projects they are a member of. The user ID is appended
+// to args when a restriction applies.
+func scopeFilter(user *models.User, args []interface{}) (string, []interface{}) {
+	if user.CanAccessAllOrganizations() {
+		return "TRUE", args
+	}
+
+	args = append(args, user.ID)
+	n := len(args)
+	return fmt.Sprintf(`(organization_id IN (
+			SELECT organization_id FROM organization_users WHERE user_id = $%d AND deleted_at IS NULL
+		) OR id IN (
+			SELECT project_id FROM project_members WHERE user_id = $%d
+		))`, n, n), args
+}
+
+// checkOrganizationWrite verifies that the user may create or modify
+// projects of the organization.
+func (s *Service) checkOrganizationWrite(ctx context.Context, user *models.User, orgID uuid.UUID) error {
+	if user.CanAccessAllOrganizations() {
+		return nil
+	}
+
+	var role models.OrgRole
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT ou.role
+		FROM organization_users ou
+		JOIN organizations o ON o.id = ou.organization_id AND o.deleted_at IS NULL
+		WHERE ou.organization_id = $1 AND ou.user_id = $2 AND ou.deleted_at IS NULL`,
+		orgID, user.ID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNoWriteAccess
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check organization role: %w", err)
+	}
+
+	if !role.CanWrite() {
+		return ErrNoWriteAccess
+	}
+	return nil
+}
+
+// CheckProjectWrite verifies that the user may modify the project, either
+// through their organization role or as a project admin or sub-admin. The
+// buildings and houses packages use it for changes to a project's units.
+func (s *Service) CheckProjectWrite(ctx context.Context, user *models.User, project *models.Project) error {
+	role, err := s.GetMemberRole(ctx, project.ID, user.ID)
+	if err != nil {
+		return err
+	}
+	if role == string(models.ProjectRoleAdmin) || role == string(models.ProjectRoleSubAdmin) {
+		return nil
+	}
+
+	if project.OrganizationID == nil {
+		if user.CanAccessAllOrganizations() {
+			return nil
+		}
+		return ErrNoWriteAccess
 	}
+	return s.checkOrganizationWrite(ctx, user, *project.OrganizationID)
 }
 
-func (s *Service) CreateProject(ctx context.Context, req *models.CreateProjectRequest) (*models.Project, error) {
+func (s *Service) CreateProject(ctx context.Context, user *models.User, req *models.CreateProjectRequest) (*models.Project, error) {
+	if req.OrganizationID == nil {
+		if !user.CanAccessAllOrganizations() {
+			return nil, ErrOrganizationRequired
+		}
+	} else if err := s.checkOrganizationWrite(ctx, user, *req.OrganizationID); err != nil {
+		return nil, err
+	}
+
 	project := &models.Project{
-		ID:         uuid.New(),
-		Name:       req.Name,
-		Address:    req.Address,
-		City:       req.City,
-		State:      req.State,
-		PostalCode: req.PostalCode,
-		OwnerName:  req.OwnerName,
-		Status:     req.Status,
-		Budget:     req.Budget,
-		StartDate:  req.StartDate,
-		EndDate:    req.EndDate,
-		Metadata:   models.JSONB(req.Metadata),
-		Documents:  models.JSONB(req.Documents),
-	}
-
-	_, err := s.db.Pool.Exec(ctx, `
+		ID:             uuid.New(),
+		OrganizationID: req.OrganizationID,
+		Name:           req.Name,
+		Address:        req.Address,
+		City:           req.City,
+		State:          req.State,
+		PostalCode:     req.PostalCode,
+		Type:           req.Type,
+		Latitude:       req.Latitude,
+		Longitude:      req.Longitude,
+		OwnerName:      req.OwnerName,
+		Status:         req.Status,
+		Budget:         req.Budget,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Metadata:       models.JSONB(req.Metadata),
+		Documents:      models.JSONB(req.Documents),
+		Version:        1,
+		CreatedAt:      time.Now(),
+		UpdatedBy:      &user.ID,
+	}
+	project.UpdatedAt = project.CreatedAt
+	if project.Status == nil {
+		initial := s.workflow.Initial
+		project.Status = &initial
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
 		INSERT INTO projects (
 			id, name, address, city, state, postal_code, owner_name, status, 
-			budget, start_date, end_date, metadata, documents, created_at, updated_at
+			budget, start_date, end_date, metadata, documents, created_at, updated_at,
+			organization_id, version, updated_by, type, latitude, longitude
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+			$19, $20, $21
 		)`,
 		project.ID, project.Name, project.Address, project.City, project.State,
 		project.PostalCode, project.OwnerName, project.Status, project.Budget,
 		project.StartDate, project.EndDate, project.Metadata, project.Documents,
-		project.CreatedAt, project.UpdatedAt)
+		project.CreatedAt, project.UpdatedAt, project.OrganizationID, project.Version,
+		project.UpdatedBy, project.Type, project.Latitude, project.Longitude)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
+	if err := checkMetadata(ctx, tx, project); err != nil {
+		return nil, err
+	}
+
+	if err := recordStatusChange(ctx, tx, project.ID, nil, *project.Status, nil, nil, user.ID); err != nil {
+		return nil, err
+	}
+
+	if len(req.Owners) > 0 {
+		if err := insertOwners(ctx, tx, project.ID, req.Owners); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, ownerSharesError(err, "failed to commit project creation")
+	}
+
 	s.logger.Info("Project created", zap.String("project_id", project.ID.String()), zap.String("name", project.Name))
 	return project, nil
 }
 
-func (s *Service) GetProject(ctx context.Context, id uuid.UUID) (*models.Project, error) {
-	var project models.Project
-	err := s.db.Pool.QueryRow(ctx, `
-		SELECT id, name, address, city, state, postal_code, owner_name, status,
-		       budget, start_date, end_date, metadata, documents, created_at, updated_at
-		FROM projects WHERE id = $1`,
-		id).Scan(
-		&project.ID, &project.Name, &project.Address, &project.City, &project.State,
-		&project.PostalCode, &project.OwnerName, &project.Status, &project.Budget,
-		&project.StartDate, &project.EndDate, &project.Metadata, &project.Documents,
-		&project.CreatedAt, &project.UpdatedAt)
+func (s *Service) GetProject(ctx context.Context, user *models.User, id uuid.UUID) (*models.Project, error) {
+	scope, args := scopeFilter(user, []interface{}{id})
+
+	project, err := scanProject(s.db.Pool.QueryRow(ctx, `
+		SELECT `+projectColumns+`
+		FROM projects WHERE id = $1 AND deleted_at IS NULL AND `+scope,
+		args...))
 
 	if err != nil {
 		return nil, fmt.Errorf("project not found: %w", err)
 	}
 
-	return &project, nil
+	return project, nil
 }
 
-func (s *Service) ListProjects(ctx context.Context, limit, offset int) ([]*models.Project, error) {
+// ListProjects returns one page of the projects matching the filter. The
+// default order pages by keyset; custom sorts and search ranking page by
+// offset, hidden inside the same opaque cursor.
+func (s *Service) ListProjects(ctx context.Context, user *models.User, filter *ListProjectsFilter, params *pagination.Params) (*pagination.Page[*models.Project], error) {
+	keyset := filter.keysetOrder()
+	if params.Cursor != nil && keyset != (params.Cursor.CreatedAt != nil) {
+		return nil, pagination.ErrInvalidCursor
+	}
+	if err := s.resolveMetadataFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	where, args := filter.where(user, nil)
+	filterArgs := args
+
+	var cursor string
+	if keyset {
+		cursor, args = params.KeysetCondition(args)
+	} else {
+		cursor = "TRUE"
+	}
+	order, args := filter.orderBy(args)
+	args = append(args, params.Fetch(), params.Offset())
+
 	rows, err := s.db.Pool.Query(ctx, `
-		SELECT id, name, address, city, state, postal_code, owner_name, status,
-		       budget, start_date, end_date, metadata, documents, created_at, updated_at
-		FROM projects 
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2`,
-		limit, offset)
+		SELECT `+projectColumns+`
+		FROM projects
+		WHERE `+where+` AND `+cursor+`
+		ORDER BY `+order+`
+		LIMIT `+fmt.Sprintf("$%d OFFSET $%d", len(args)-1, len(args)),
+		args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list projects: %w", err)
@@ -95,89 +259,206 @@ func (s *Service) ListProjects(ctx context.Context, limit, offset int) ([]*model
 
 	var projects []*models.Project
 	for rows.Next() {
-		var project models.Project
-		err := rows.Scan(
-			&project.ID, &project.Name, &project.Address, &project.City, &project.State,
-			&project.PostalCode, &project.OwnerName, &project.Status, &project.Budget,
-			&project.StartDate, &project.EndDate, &project.Metadata, &project.Documents,
-			&project.CreatedAt, &project.UpdatedAt)
+		project, err := scanProject(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan project: %w", err)
 		}
-		projects = append(projects, &project)
+		projects = append(projects, project)
 	}
-
-	return projects, nil
-}
-
-func (s *Service) UpdateProject(ctx context.Context, id uuid.UUID, req *models.UpdateProjectRequest) (*models.Project, error) {
-	// Get existing project
-	project, err := s.GetProject(ctx, id)
-	if err != nil {
-		return nil, err
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
 
-	// Update fields if provided
-	if req.Name != nil {
-		project.Name = *req.Name
+	next := pagination.OffsetCursor[*models.Project](params)
+	if keyset {
+		next = func(last *models.Project) pagination.Cursor {
+			return pagination.After(last.CreatedAt, last.ID)
+		}
 	}
-	if req.Address != nil {
-		project.Address = req.Address
+	page := pagination.NewPage(projects, params, next)
+
+	if params.IncludeTotal {
+		var total int64
+		err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM projects WHERE "+where, filterArgs...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count projects: %w", err)
+		}
+		page.Total = &total
 	}
-	if req.City != nil {
-		project.City = req.City
+
+	return page, nil
+}
+
+// UpdateProject applies the request as a new version of the project. When
+// expectedVersions is not nil, the stored version must be one of them or
+// ErrVersionMismatch is returned.
+func (s *Service) UpdateProject(ctx context.Context, user *models.User, id uuid.UUID, req *models.UpdateProjectRequest, expectedVersions []int) (*models.Project, error) {
+	return s.updateProject(ctx, user, id, expectedVersions, func(project *models.Project) error {
+		// Update fields if provided
+		if req.Name != nil {
+			project.Name = *req.Name
+		}
+		if req.Address != nil {
+			project.Address = req.Address
+		}
+		if req.City != nil {
+			project.City = req.City
+		}
+		if req.State != nil {
+			project.State = req.State
+		}
+		if req.PostalCode != nil {
+			project.PostalCode = req.PostalCode
+		}
+		if req.Type != nil {
+			project.Type = req.Type
+		}
+		if req.Latitude != nil {
+			project.Latitude = req.Latitude
+		}
+		if req.Longitude != nil {
+			project.Longitude = req.Longitude
+		}
+		if req.OwnerName != nil {
+			project.OwnerName = req.OwnerName
+		}
+		if req.Status != nil {
+			project.Status = req.Status
+		}
+		if req.Budget != nil {
+			project.Budget = req.Budget
+		}
+		if req.StartDate != nil {
+			project.StartDate = req.StartDate
+		}
+		if req.EndDate != nil {
+			project.EndDate = req.EndDate
+		}
+		if req.Metadata != nil {
+			project.Metadata = models.JSONB(req.Metadata)
+		}
+		if req.Documents != nil {
+			project.Documents = models.JSONB(req.Documents)
+		}
+
+		// The request alone cannot catch rules that span stored and new
+		// values, such as an end date before the stored start date
+		return newPatchDocument(project).applyTo(project)
+	})
+}
+
+// updateProject is writeProject for edits of the project's fields. The
+// status is left to TransitionProject.
+func (s *Service) updateProject(ctx context.Context, user *models.User, id uuid.UUID, expectedVersions []int, apply func(*models.Project) error) (*models.Project, error) {
+	return s.writeProject(ctx, user, id, expectedVersions, func(tx pgx.Tx, project *models.Project) error {
+		status := s.currentStatus(project)
+		projectType := project.Type
+		metadata := metadataJSON(project)
+		if err := apply(project); err != nil {
+			return err
+		}
+		if s.currentStatus(project) != status {
+			return validation.Errors{{Field: "status", Rule: "workflow", Message: "can only be changed through a status transition"}}
+		}
+		typeChanged := !reflect.DeepEqual(project.Type, projectType)
+		if typeChanged {
+			if err := checkTypeChange(ctx, tx, project); err != nil {
+				return err
+			}
+		}
+		// Metadata stored before its schema was registered is only checked
+		// once it is edited
+		if typeChanged || metadataJSON(project) != metadata {
+			return checkMetadata(ctx, tx, project)
+		}
+		return nil
+	})
+}
+
+// checkTypeChange rejects a type that doesn't allow apartment buildings
+// while the project still has some. Buildings are created under a share
+// lock on the project, which the update holds exclusively.
+func checkTypeChange(ctx context.Context, tx pgx.Tx, project *models.Project) error {
+	if project.Type != nil && project.Type.AllowsBuildings() {
+		return nil
 	}
-	if req.State != nil {
-		project.State = req.State
+
+	var hasBuildings bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM apartment_buildings WHERE project_id = $1 AND deleted_at IS NULL)",
+		project.ID).Scan(&hasBuildings)
+	if err != nil {
+		return fmt.Errorf("failed to check project buildings: %w", err)
 	}
-	if req.PostalCode != nil {
-		project.PostalCode = req.PostalCode
+	if hasBuildings {
+		return validation.Errors{{Field: "type", Rule: "buildings", Message: "must be Colony or HousingEstate while the project has apartment buildings"}}
 	}
-	if req.OwnerName != nil {
-		project.OwnerName = req.OwnerName
+	return nil
+}
+
+// writeProject locks the project, lets apply modify it within the
+// transaction and saves the result as a new version
+func (s *Service) writeProject(ctx context.Context, user *models.User, id uuid.UUID, expectedVersions []int, apply func(pgx.Tx, *models.Project) error) (*models.Project, error) {
+	// Get existing project
+	project, err := s.GetProject(ctx, user, id)
+	if err != nil {
+		return nil, err
 	}
-	if req.Status != nil {
-		project.Status = req.Status
+
+	if err := s.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
 	}
-	if req.Budget != nil {
-		project.Budget = req.Budget
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if req.StartDate != nil {
-		project.StartDate = req.StartDate
+	defer tx.Rollback(ctx)
+
+	// Re-read under lock so the archived version is exactly the row replaced
+	project, err = getProjectForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
 	}
-	if req.EndDate != nil {
-		project.EndDate = req.EndDate
+
+	if err := checkVersion(project, expectedVersions); err != nil {
+		return nil, err
 	}
-	if req.Metadata != nil {
-		project.Metadata = models.JSONB(req.Metadata)
+
+	if err := apply(tx, project); err != nil {
+		return nil, err
 	}
-	if req.Documents != nil {
-		project.Documents = models.JSONB(req.Documents)
+
+	if err := s.saveProjectVersion(ctx, tx, user, project); err != nil {
+		return nil, err
 	}
 
-	project.UpdatedAt = project.UpdatedAt
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit project update: %w", err)
+	}
 
-	_, err = s.db.Pool.Exec(ctx, `
-		UPDATE projects SET 
-			name = $2, address = $3, city = $4, state = $5, postal_code = $6,
-			owner_name = $7, status = $8, budget = $9, start_date = $10,
-			end_date = $11, metadata = $12, documents = $13, updated_at = $14
-		WHERE id = $1`,
-		project.ID, project.Name, project.Address, project.City, project.State,
-		project.PostalCode, project.OwnerName, project.Status, project.Budget,
-		project.StartDate, project.EndDate, project.Metadata, project.Documents,
-		project.UpdatedAt)
+	s.logger.Info("Project updated",
+		zap.String("project_id", project.ID.String()),
+		zap.String("name", project.Name),
+		zap.Int("version", project.Version))
+	return project, nil
+}
 
+func (s *Service) DeleteProject(ctx context.Context, user *models.User, id uuid.UUID) error {
+	project, err := s.GetProject(ctx, user, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update project: %w", err)
+		return err
 	}
 
-	s.logger.Info("Project updated", zap.String("project_id", project.ID.String()), zap.String("name", project.Name))
-	return project, nil
-}
+	if err := s.CheckProjectWrite(ctx, user, project); err != nil {
+		return err
+	}
 
-func (s *Service) DeleteProject(ctx context.Context, id uuid.UUID) error {
-	result, err := s.db.Pool.Exec(ctx, "DELETE FROM projects WHERE id = $1", id)
+	// Soft delete: the row and its history stay in the trash until restored
+	// or purged
+	result, err := s.db.Pool.Exec(ctx,
+		"UPDATE projects SET deleted_at = $2, deleted_by = $3 WHERE id = $1 AND deleted_at IS NULL",
+		id, time.Now(), user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete project: %w", err)
 	}
@@ -186,13 +467,15 @@ func (s *Service) DeleteProject(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("project not found")
 	}
 
-	s.logger.Info("Project deleted", zap.String("project_id", id.String()))
+	s.logger.Info("Project moved to trash", zap.String("project_id", id.String()), zap.String("deleted_by", user.ID.String()))
 	return nil
 }
 
-func (s *Service) GetProjectCount(ctx context.Context) (int64, error) {
+func (s *Service) GetProjectCount(ctx context.Context, user *models.User) (int64, error) {
+	scope, args := scopeFilter(user, nil)
+
 	var count int64
-	err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM projects").Scan(&count)
+	err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM projects WHERE deleted_at IS NULL AND "+scope, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get project count: %w", err)
 	}
@@ -0,0 +1,173 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ListTrash returns one page of the soft-deleted projects visible to the
+// user, most recently deleted first. The list pages by offset.
+func (s *Service) ListTrash(ctx context.Context, user *models.User, params *pagination.Params) (*pagination.Page[*models.Project], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	scope, args := scopeFilter(user, nil)
+	where := "deleted_at IS NOT NULL AND " + scope
+	filterArgs := args
+	args = append(args, params.Fetch(), params.Offset())
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+projectColumns+`
+		FROM projects
+		WHERE `+where+`
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT `+fmt.Sprintf("$%d OFFSET $%d", len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*models.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list deleted projects: %w", err)
+	}
+
+	page := pagination.NewPage(projects, params, pagination.OffsetCursor[*models.Project](params))
+
+	if params.IncludeTotal {
+		var total int64
+		err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM projects WHERE "+where, filterArgs...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count deleted projects: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (s *Service) getDeletedProject(ctx context.Context, user *models.User, id uuid.UUID) (*models.Project, error) {
+	scope, args := scopeFilter(user, []interface{}{id})
+
+	project, err := scanProject(s.db.Pool.QueryRow(ctx, `
+		SELECT `+projectColumns+`
+		FROM projects WHERE id = $1 AND deleted_at IS NOT NULL AND `+scope,
+		args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted project: %w", err)
+	}
+
+	return project, nil
+}
+
+// RestoreProject moves a project out of the trash
+func (s *Service) RestoreProject(ctx context.Context, user *models.User, id uuid.UUID) (*models.Project, error) {
+	project, err := s.getDeletedProject(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckProjectWrite(ctx, user, project); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Pool.Exec(ctx,
+		"UPDATE projects SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL",
+		id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore project: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrProjectNotFound
+	}
+
+	project.DeletedAt = nil
+	project.DeletedBy = nil
+
+	s.logger.Info("Project restored from trash",
+		zap.String("project_id", id.String()),
+		zap.String("restored_by", user.ID.String()))
+
+	return project, nil
+}
+
+// PurgeProject permanently deletes a project that is already in the trash,
+// together with its version history
+func (s *Service) PurgeProject(ctx context.Context, user *models.User, id uuid.UUID) error {
+	project, err := s.getDeletedProject(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CheckProjectWrite(ctx, user, project); err != nil {
+		return err
+	}
+
+	result, err := s.db.Pool.Exec(ctx, "DELETE FROM projects WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to purge project: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrProjectNotFound
+	}
+
+	s.logger.Info("Project purged",
+		zap.String("project_id", id.String()),
+		zap.String("purged_by", user.ID.String()))
+	return nil
+}
+
+// PurgeExpired permanently deletes projects that have been in the trash for
+// longer than retention
+func (s *Service) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	result, err := s.db.Pool.Exec(ctx,
+		"DELETE FROM projects WHERE deleted_at IS NOT NULL AND deleted_at < $1",
+		time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired projects: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RunTrashPurge calls PurgeExpired every interval until ctx is cancelled
+func (s *Service) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx, retention)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to purge trash", zap.Error(err))
+		} else if purged > 0 {
+			s.logger.Info("Purged expired projects from trash", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
@@ -0,0 +1,125 @@
+package projects
+
+import (
+	"errors"
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// @Summary List deleted projects
+// @Description Page through projects in the trash, most recently deleted first. They are purged permanently after the retention period.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of projects to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of deleted projects"
+// @Success 200 {object} pagination.Page[models.Project]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /projects/trash [get]
+func (h *Handler) ListTrash(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListTrash(c.Request.Context(), user, params)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to list deleted projects", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deleted projects"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Restore a deleted project
+// @Description Move a project out of the trash
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/restore [post]
+func (h *Handler) RestoreProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	project, err := h.service.RestoreProject(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondTrashError(c, "Failed to restore project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary Permanently delete a project
+// @Description Purge a project from the trash, including its version history. This cannot be undone.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/purge [delete]
+func (h *Handler) PurgeProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	if err := h.service.PurgeProject(c.Request.Context(), user, id); err != nil {
+		h.respondTrashError(c, "Failed to purge project", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondTrashError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found in trash"},
+		api.ErrorResponse{Err: ErrNoWriteAccess, Status: http.StatusForbidden},
+	)
+}
+
@@ -0,0 +1,357 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var ErrVersionNotFound = errors.New("project version not found")
+
+const versionColumns = `project_id, version, organization_id, name, address, city, state, postal_code,
+		       owner_name, status, budget, start_date, end_date, metadata, documents,
+		       updated_at, updated_by, archived_at, archived_by, type, latitude, longitude`
+
+func scanVersion(row pgx.Row) (*models.ProjectVersion, error) {
+	var v models.ProjectVersion
+	err := row.Scan(&v.ProjectID, &v.Version, &v.OrganizationID, &v.Name, &v.Address, &v.City, &v.State,
+		&v.PostalCode, &v.OwnerName, &v.Status, &v.Budget, &v.StartDate, &v.EndDate, &v.Metadata,
+		&v.Documents, &v.UpdatedAt, &v.UpdatedBy, &v.ArchivedAt, &v.ArchivedBy, &v.Type, &v.Latitude,
+		&v.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// getProjectForUpdate reads and locks the project row for the rest of the transaction
+func getProjectForUpdate(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*models.Project, error) {
+	project, err := scanProject(tx.QueryRow(ctx, `
+		SELECT `+projectColumns+`
+		FROM projects WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock project: %w", err)
+	}
+	return project, nil
+}
+
+// checkVersion enforces an If-Match precondition against the locked row
+func checkVersion(project *models.Project, expectedVersions []int) error {
+	if expectedVersions == nil {
+		return nil
+	}
+	for _, v := range expectedVersions {
+		if v == project.Version {
+			return nil
+		}
+	}
+	return ErrVersionMismatch
+}
+
+// saveProjectVersion archives the stored row into project_versions and
+// writes the project as the next version. It must run in the transaction
+// that locked the row with getProjectForUpdate.
+func (s *Service) saveProjectVersion(ctx context.Context, tx pgx.Tx, user *models.User, project *models.Project) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO project_versions (
+			project_id, version, name, status, budget, start_date, end_date, owner_name,
+			address, city, state, postal_code, organization_id, metadata, documents,
+			created_at, updated_at, updated_by, archived_at, archived_by, type, latitude, longitude
+		)
+		SELECT id, version, name, status, budget, start_date, end_date, owner_name,
+		       address, city, state, postal_code, organization_id, COALESCE(metadata, '{}'),
+		       COALESCE(documents, '{}'), created_at, updated_at, updated_by, NOW(), $2,
+		       type, latitude, longitude
+		FROM projects WHERE id = $1`,
+		project.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to archive project version: %w", err)
+	}
+
+	project.UpdatedAt = time.Now()
+	project.UpdatedBy = &user.ID
+
+	err = tx.QueryRow(ctx, `
+		UPDATE projects SET 
+			name = $2, address = $3, city = $4, state = $5, postal_code = $6,
+			owner_name = $7, status = $8, budget = $9, start_date = $10,
+			end_date = $11, metadata = $12, documents = $13, updated_at = $14,
+			updated_by = $15, type = $16, latitude = $17, longitude = $18,
+			version = version + 1
+		WHERE id = $1
+		RETURNING version`,
+		project.ID, project.Name, project.Address, project.City, project.State,
+		project.PostalCode, project.OwnerName, project.Status, project.Budget,
+		project.StartDate, project.EndDate, project.Metadata, project.Documents,
+		project.UpdatedAt, project.UpdatedBy, project.Type, project.Latitude,
+		project.Longitude).Scan(&project.Version)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// ListVersions returns one page of the project's versions, newest first,
+// starting with the current one. The list pages by offset.
+func (s *Service) ListVersions(ctx context.Context, user *models.User, projectID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.ProjectVersion], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+	project, err := s.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The current version is not archived; it takes the first slot of the
+	// first page, so archived rows start one offset earlier
+	var versions []*models.ProjectVersion
+	fetch, offset := params.Fetch(), params.Offset()-1
+	if params.Offset() == 0 {
+		versions = append(versions, models.VersionFromProject(project))
+		fetch, offset = fetch-1, 0
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT `+versionColumns+`
+		FROM project_versions
+		WHERE project_id = $1
+		ORDER BY version DESC
+		LIMIT $2 OFFSET $3`, projectID, fetch, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project versions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list project versions: %w", err)
+	}
+
+	page := pagination.NewPage(versions, params, pagination.OffsetCursor[*models.ProjectVersion](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx,
+			"SELECT COUNT(*) + 1 FROM project_versions WHERE project_id = $1", projectID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count project versions: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// GetVersion returns the project as it was at the given version. The
+// current version number returns the live project.
+func (s *Service) GetVersion(ctx context.Context, user *models.User, projectID uuid.UUID, version int) (*models.ProjectVersion, error) {
+	project, err := s.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == project.Version {
+		return models.VersionFromProject(project), nil
+	}
+
+	v, err := scanVersion(s.db.Pool.QueryRow(ctx, `
+		SELECT `+versionColumns+`
+		FROM project_versions
+		WHERE project_id = $1 AND version = $2`, projectID, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project version: %w", err)
+	}
+
+	return v, nil
+}
+
+// DiffVersions lists the fields that changed between two versions
+func (s *Service) DiffVersions(ctx context.Context, user *models.User, projectID uuid.UUID, from, to int) (*models.ProjectVersionDiff, error) {
+	fromVersion, err := s.GetVersion(ctx, user, projectID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toVersion, err := s.GetVersion(ctx, user, projectID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.ProjectVersionDiff{
+		ProjectID:   projectID,
+		FromVersion: from,
+		ToVersion:   to,
+		Changes:     []models.VersionFieldChange{},
+	}
+
+	fromFields := versionFields(fromVersion)
+	toFields := versionFields(toVersion)
+	for i, field := range fromFields {
+		if !reflect.DeepEqual(field.value, toFields[i].value) {
+			diff.Changes = append(diff.Changes, models.VersionFieldChange{
+				Field: field.name,
+				From:  field.value,
+				To:    toFields[i].value,
+			})
+		}
+	}
+
+	return diff, nil
+}
+
+// RestoreVersion rolls the project back to an earlier version. The rollback
+// is itself recorded as a new version, so it can be undone.
+func (s *Service) RestoreVersion(ctx context.Context, user *models.User, projectID uuid.UUID, version int) (*models.Project, error) {
+	current, err := s.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckProjectWrite(ctx, user, current); err != nil {
+		return nil, err
+	}
+
+	target, err := s.GetVersion(ctx, user, projectID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	project, err := getProjectForUpdate(ctx, tx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := metadataJSON(project)
+
+	// The organization is kept so a rollback can't move a project between
+	// tenants, the status so it can't bypass the status workflow, and the
+	// type so it can't conflict with the project's buildings
+	project.Name = target.Name
+	project.Address = target.Address
+	project.City = target.City
+	project.State = target.State
+	project.PostalCode = target.PostalCode
+	project.Latitude = target.Latitude
+	project.Longitude = target.Longitude
+	project.OwnerName = target.OwnerName
+	project.Budget = target.Budget
+	project.StartDate = target.StartDate
+	project.EndDate = target.EndDate
+	project.Metadata = target.Metadata
+	project.Documents = target.Documents
+
+	if metadataJSON(project) != metadata {
+		if err := checkMetadata(ctx, tx, project); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.saveProjectVersion(ctx, tx, user, project); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit project restore: %w", err)
+	}
+
+	s.logger.Info("Project restored to earlier version",
+		zap.String("project_id", projectID.String()),
+		zap.Int("restored_version", version),
+		zap.Int("new_version", project.Version),
+		zap.String("restored_by", user.ID.String()))
+
+	return project, nil
+}
+
+type versionField struct {
+	name  string
+	value interface{}
+}
+
+// versionFields lists the user-editable fields of a version in a fixed
+// order, with pointers dereferenced so that equal values compare equal.
+func versionFields(v *models.ProjectVersion) []versionField {
+	return []versionField{
+		{"name", v.Name},
+		{"address", derefString(v.Address)},
+		{"city", derefString(v.City)},
+		{"state", derefString(v.State)},
+		{"postal_code", derefString(v.PostalCode)},
+		{"type", derefType(v.Type)},
+		{"latitude", derefFloat(v.Latitude)},
+		{"longitude", derefFloat(v.Longitude)},
+		{"owner_name", derefString(v.OwnerName)},
+		{"status", derefString(v.Status)},
+		{"budget", derefFloat(v.Budget)},
+		{"start_date", formatDate(v.StartDate)},
+		{"end_date", formatDate(v.EndDate)},
+		{"metadata", emptyAsNil(v.Metadata)},
+		{"documents", emptyAsNil(v.Documents)},
+	}
+}
+
+func derefString(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func derefType(t *models.ProjectType) interface{} {
+	if t == nil {
+		return nil
+	}
+	return string(*t)
+}
+
+func derefFloat(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+func formatDate(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+// emptyAsNil treats an empty JSONB object like NULL, since archived
+// versions store '{}' where the project row held NULL.
+func emptyAsNil(j models.JSONB) interface{} {
+	if len(j) == 0 {
+		return nil
+	}
+	return map[string]interface{}(j)
+}
+
@@ -0,0 +1,192 @@
+package projects
+
+import (
+	"net/http"
+	"strconv"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary List project versions
+// @Description Page through the version history of a project, newest first. The first entry is the current version.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param limit query int false "Number of versions to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of versions"
+// @Success 200 {object} pagination.Page[models.ProjectVersion]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/versions [get]
+func (h *Handler) ListVersions(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	versions, err := h.service.ListVersions(c.Request.Context(), user, id, params)
+	if err != nil {
+		h.respondVersionError(c, "Failed to list project versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary Get a project version
+// @Description Get a project as it was at the given version
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} models.ProjectVersion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/versions/{version} [get]
+func (h *Handler) GetVersion(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+
+	v, err := h.service.GetVersion(c.Request.Context(), user, id, version)
+	if err != nil {
+		h.respondVersionError(c, "Failed to get project version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v)
+}
+
+// @Summary Compare project versions
+// @Description List the fields that changed between two versions of a project
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param from query int true "Older version number"
+// @Param to query int true "Newer version number"
+// @Success 200 {object} models.ProjectVersionDiff
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/versions/diff [get]
+func (h *Handler) DiffVersions(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from version"})
+		return
+	}
+
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to version"})
+		return
+	}
+
+	diff, err := h.service.DiffVersions(c.Request.Context(), user, id, from, to)
+	if err != nil {
+		h.respondVersionError(c, "Failed to compare project versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary Restore a project version
+// @Description Roll a project back to an earlier version. The rollback is recorded as a new version.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param version path int true "Version number to restore"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]interface{}
+// @Router /projects/{id}/versions/{version}/restore [post]
+func (h *Handler) RestoreVersion(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+
+	project, err := h.service.RestoreVersion(c.Request.Context(), user, id, version)
+	if err != nil {
+		h.respondVersionError(c, "Failed to restore project version", err)
+		return
+	}
+
+	c.Header("ETag", projectETag(project))
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *Handler) respondVersionError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrVersionNotFound, Status: http.StatusNotFound, Message: "Version not found"},
+		api.ErrorResponse{Err: ErrNoWriteAccess, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pagination.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+	)
+}
+
@@ -0,0 +1,269 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownTransition    = errors.New("unknown status transition")
+	ErrTransitionNotAllowed = errors.New("transition is not allowed from the project's current status")
+	ErrReasonRequired       = errors.New("a reason is required for this transition")
+)
+
+// Transition moves a project from any of the From statuses to To. Who may
+// run it is decided by the RBAC policy for the action "transition/<name>".
+type Transition struct {
+	Name           string   `json:"name"`
+	From           []string `json:"from"`
+	To             string   `json:"to"`
+	RequiresReason bool     `json:"requires_reason"`
+}
+
+// Workflow is the project status state machine. Projects are created in
+// Initial unless the request names another status; after that the status
+// only changes through a transition.
+type Workflow struct {
+	Initial     string       `json:"initial"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// DefaultWorkflow is used unless PROJECT_WORKFLOW_PATH names a JSON file
+// with the same shape
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Initial: "planning",
+		Transitions: []Transition{
+			{Name: "start", From: []string{"planning"}, To: "active"},
+			{Name: "hold", From: []string{"active"}, To: "on-hold"},
+			{Name: "resume", From: []string{"on-hold"}, To: "active"},
+			{Name: "complete", From: []string{"active"}, To: "completed"},
+			{Name: "cancel", From: []string{"planning", "active", "on-hold"}, To: "cancelled", RequiresReason: true},
+			{Name: "reopen", From: []string{"completed", "cancelled"}, To: "planning", RequiresReason: true},
+		},
+	}
+}
+
+// LoadWorkflow reads a workflow definition from a JSON file
+func LoadWorkflow(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+
+	var workflow Workflow
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	if err := workflow.validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+
+	return &workflow, nil
+}
+
+func (w *Workflow) validate() error {
+	if !models.IsValidProjectStatus(w.Initial) {
+		return fmt.Errorf("unknown initial status %q", w.Initial)
+	}
+
+	names := make(map[string]bool)
+	for _, transition := range w.Transitions {
+		if transition.Name == "" || strings.Contains(transition.Name, "/") {
+			return fmt.Errorf("invalid transition name %q", transition.Name)
+		}
+		if names[transition.Name] {
+			return fmt.Errorf("duplicate transition %q", transition.Name)
+		}
+		names[transition.Name] = true
+
+		if !models.IsValidProjectStatus(transition.To) {
+			return fmt.Errorf("transition %q: unknown status %q", transition.Name, transition.To)
+		}
+		if len(transition.From) == 0 {
+			return fmt.Errorf("transition %q has no source status", transition.Name)
+		}
+		for _, from := range transition.From {
+			if !models.IsValidProjectStatus(from) {
+				return fmt.Errorf("transition %q: unknown status %q", transition.Name, from)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Workflow) transition(name string) (*Transition, bool) {
+	for i := range w.Transitions {
+		if w.Transitions[i].Name == name {
+			return &w.Transitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// available returns the transitions that can run from status
+func (w *Workflow) available(status string) []Transition {
+	transitions := []Transition{}
+	for _, transition := range w.Transitions {
+		if transition.allowedFrom(status) {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions
+}
+
+func (t *Transition) allowedFrom(status string) bool {
+	for _, from := range t.From {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SetWorkflow replaces the default workflow
+func (s *Service) SetWorkflow(workflow *Workflow) {
+	s.workflow = workflow
+}
+
+// currentStatus treats projects without a status as being in the initial one
+func (s *Service) currentStatus(project *models.Project) string {
+	if project.Status == nil {
+		return s.workflow.Initial
+	}
+	return *project.Status
+}
+
+// ListTransitions returns the transitions available from the project's
+// current status. Whether the caller may run them is up to the RBAC policy.
+func (s *Service) ListTransitions(ctx context.Context, user *models.User, projectID uuid.UUID) ([]Transition, error) {
+	project, err := s.GetProject(ctx, user, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.workflow.available(s.currentStatus(project)), nil
+}
+
+// TransitionProject runs the named transition and records it in the status
+// history. expectedVersions works as in UpdateProject.
+func (s *Service) TransitionProject(ctx context.Context, user *models.User, projectID uuid.UUID, name string, reason *string, expectedVersions []int) (*models.Project, error) {
+	transition, ok := s.workflow.transition(name)
+	if !ok {
+		return nil, ErrUnknownTransition
+	}
+	if reason != nil && strings.TrimSpace(*reason) == "" {
+		reason = nil
+	}
+	if transition.RequiresReason && reason == nil {
+		return nil, ErrReasonRequired
+	}
+
+	var from string
+	project, err := s.writeProject(ctx, user, projectID, expectedVersions, func(tx pgx.Tx, project *models.Project) error {
+		from = s.currentStatus(project)
+		if !transition.allowedFrom(from) {
+			return fmt.Errorf("%w: cannot %s a project that is %s", ErrTransitionNotAllowed, transition.Name, from)
+		}
+
+		to := transition.To
+		project.Status = &to
+		return recordStatusChange(ctx, tx, project.ID, &from, transition.To, &transition.Name, reason, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Project status changed",
+		zap.String("project_id", projectID.String()),
+		zap.String("transition", name),
+		zap.String("from", from),
+		zap.String("to", transition.To),
+		zap.String("changed_by", user.ID.String()))
+
+	return project, nil
+}
+
+func recordStatusChange(ctx context.Context, tx pgx.Tx, projectID uuid.UUID, from *string, to string, transition, reason *string, changedBy uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO project_status_history (project_id, from_status, to_status, transition, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		projectID, from, to, transition, reason, changedBy)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+// StatusHistory returns one page of the project's status changes, oldest
+// first, with the time spent in each status. The list pages by offset.
+func (s *Service) StatusHistory(ctx context.Context, user *models.User, projectID uuid.UUID, params *pagination.Params) (*pagination.Page[*models.ProjectStatusChange], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+	if _, err := s.GetProject(ctx, user, projectID); err != nil {
+		return nil, err
+	}
+
+	// LEAD is evaluated over the whole history before LIMIT applies, so the
+	// last change of a page still sees when the next page's first began
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT id, project_id, from_status, to_status, transition, reason, changed_by, changed_at,
+		       LEAD(changed_at) OVER (ORDER BY changed_at, id)
+		FROM project_status_history
+		WHERE project_id = $1
+		ORDER BY changed_at, id
+		LIMIT $2 OFFSET $3`,
+		projectID, params.Fetch(), params.Offset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var history []*models.ProjectStatusChange
+	for rows.Next() {
+		var change models.ProjectStatusChange
+		err := rows.Scan(&change.ID, &change.ProjectID, &change.FromStatus, &change.ToStatus,
+			&change.Transition, &change.Reason, &change.ChangedBy, &change.ChangedAt, &change.LeftAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+
+		end := now
+		if change.LeftAt != nil {
+			end = *change.LeftAt
+		}
+		change.DurationSeconds = int64(end.Sub(change.ChangedAt).Seconds())
+		history = append(history, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+
+	page := pagination.NewPage(history, params, pagination.OffsetCursor[*models.ProjectStatusChange](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM project_status_history WHERE project_id = $1", projectID).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count status changes: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
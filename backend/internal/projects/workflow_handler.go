@@ -0,0 +1,156 @@
+package projects
+
+import (
+	"errors"
+	"net/http"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary List available status transitions
+// @Description Get the status transitions that can run from the project's current status
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} Transition
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/transitions [get]
+func (h *Handler) ListTransitions(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	transitions, err := h.service.ListTransitions(c.Request.Context(), user, id)
+	if err != nil {
+		h.respondWorkflowError(c, "Failed to list transitions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+// @Summary Change project status
+// @Description Run a status transition such as start, hold, complete or cancel. Some transitions require a reason.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param transition path string true "Transition name"
+// @Param If-Match header string false "ETag from a previous read; the transition fails with 412 if the project changed since"
+// @Param request body models.TransitionProjectRequest false "Reason for the transition"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /projects/{id}/transitions/{transition} [post]
+func (h *Handler) TransitionProject(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	expectedVersions, ok := ifMatchVersions(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional for transitions that need no reason
+	var req models.TransitionProjectRequest
+	if c.Request.ContentLength != 0 && !validation.BindJSON(c, &req) {
+		return
+	}
+
+	project, err := h.service.TransitionProject(c.Request.Context(), user, id, c.Param("transition"), req.Reason, expectedVersions)
+	if err != nil {
+		h.respondWorkflowError(c, "Failed to change project status", err)
+		return
+	}
+
+	c.Header("ETag", projectETag(project))
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary Get project status history
+// @Description Page through the status changes of the project, oldest first, with the time spent in each status
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param limit query int false "Number of status changes to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of status changes"
+// @Success 200 {object} pagination.Page[models.ProjectStatusChange]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /projects/{id}/status-history [get]
+func (h *Handler) StatusHistory(c *gin.Context) {
+	user, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.StatusHistory(c.Request.Context(), user, id, params)
+	if err != nil {
+		h.respondWorkflowError(c, "Failed to get status history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *Handler) respondWorkflowError(c *gin.Context, msg string, err error) {
+	if errors.Is(err, ErrReasonRequired) {
+		err = validation.Errors{{Field: "reason", Rule: "required", Message: "is required"}}
+	}
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrUnknownTransition, Status: http.StatusNotFound},
+		api.ErrorResponse{Err: ErrTransitionNotAllowed, Status: http.StatusConflict},
+		api.ErrorResponse{Err: ErrVersionMismatch, Status: http.StatusPreconditionFailed},
+		api.ErrorResponse{Err: ErrNoWriteAccess, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrProjectNotFound, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pgx.ErrNoRows, Status: http.StatusNotFound, Message: "Project not found"},
+		api.ErrorResponse{Err: pagination.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+	)
+}
+
@@ -0,0 +1,185 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"project-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestDefaultWorkflowIsValid(t *testing.T) {
+	if err := DefaultWorkflow().validate(); err != nil {
+		t.Fatalf("default workflow: %v", err)
+	}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	valid := Transition{Name: "start", From: []string{"planning"}, To: "active"}
+
+	tests := []struct {
+		name     string
+		workflow Workflow
+		want     string
+	}{
+		{"unknown initial", Workflow{Initial: "draft"}, "unknown initial status"},
+		{"empty name", Workflow{Initial: "planning", Transitions: []Transition{
+			{From: []string{"planning"}, To: "active"},
+		}}, "invalid transition name"},
+		{"slash in name", Workflow{Initial: "planning", Transitions: []Transition{
+			{Name: "a/b", From: []string{"planning"}, To: "active"},
+		}}, "invalid transition name"},
+		{"duplicate name", Workflow{Initial: "planning", Transitions: []Transition{valid, valid}}, "duplicate transition"},
+		{"unknown target", Workflow{Initial: "planning", Transitions: []Transition{
+			{Name: "start", From: []string{"planning"}, To: "done"},
+		}}, "unknown status"},
+		{"no source", Workflow{Initial: "planning", Transitions: []Transition{
+			{Name: "start", To: "active"},
+		}}, "has no source status"},
+		{"unknown source", Workflow{Initial: "planning", Transitions: []Transition{
+			{Name: "start", From: []string{"draft"}, To: "active"},
+		}}, "unknown status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workflow.validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowAvailable(t *testing.T) {
+	workflow := DefaultWorkflow()
+
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"planning", []string{"start", "cancel"}},
+		{"active", []string{"hold", "complete", "cancel"}},
+		{"on-hold", []string{"resume", "cancel"}},
+		{"completed", []string{"reopen"}},
+		{"cancelled", []string{"reopen"}},
+		{"unknown", []string{}},
+	}
+
+	for _, tt := range tests {
+		names := []string{}
+		for _, transition := range workflow.available(tt.status) {
+			names = append(names, transition.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("available(%q) = %v, want %v", tt.status, names, tt.want)
+		}
+	}
+}
+
+func TestWorkflowTransition(t *testing.T) {
+	workflow := DefaultWorkflow()
+
+	tests := []struct {
+		name    string
+		from    string
+		allowed bool
+	}{
+		{"start", "planning", true},
+		{"start", "active", false},
+		{"complete", "active", true},
+		{"complete", "on-hold", false},
+		{"cancel", "on-hold", true},
+		{"cancel", "completed", false},
+		{"reopen", "cancelled", true},
+	}
+
+	for _, tt := range tests {
+		transition, ok := workflow.transition(tt.name)
+		if !ok {
+			t.Fatalf("transition %q not found", tt.name)
+		}
+		if got := transition.allowedFrom(tt.from); got != tt.allowed {
+			t.Errorf("%s from %s allowed = %v, want %v", tt.name, tt.from, got, tt.allowed)
+		}
+	}
+
+	if _, ok := workflow.transition("archive"); ok {
+		t.Error("unknown transition found")
+	}
+}
+
+func TestLoadWorkflow(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	workflow, err := LoadWorkflow(write("ok.json", `{
+		"initial": "active",
+		"transitions": [{"name": "finish", "from": ["active"], "to": "completed"}]
+	}`))
+	if err != nil {
+		t.Fatalf("LoadWorkflow: %v", err)
+	}
+	if workflow.Initial != "active" || len(workflow.Transitions) != 1 || workflow.Transitions[0].To != "completed" {
+		t.Errorf("LoadWorkflow = %+v", workflow)
+	}
+
+	if _, err := LoadWorkflow(write("invalid.json", `{"initial": "draft"}`)); err == nil {
+		t.Error("invalid workflow loaded")
+	}
+	if _, err := LoadWorkflow(write("broken.json", `{`)); err == nil {
+		t.Error("malformed workflow loaded")
+	}
+	if _, err := LoadWorkflow(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing workflow loaded")
+	}
+}
+
+func TestCurrentStatus(t *testing.T) {
+	s := &Service{workflow: DefaultWorkflow()}
+	if got := s.currentStatus(&models.Project{}); got != "planning" {
+		t.Errorf("currentStatus(no status) = %q, want planning", got)
+	}
+	status := "active"
+	if got := s.currentStatus(&models.Project{Status: &status}); got != "active" {
+		t.Errorf("currentStatus = %q, want active", got)
+	}
+}
+
+func TestTransitionProjectRejectsBeforeWriting(t *testing.T) {
+	// These checks run before the database is touched
+	s := &Service{workflow: DefaultWorkflow()}
+	user := &models.User{ID: uuid.New()}
+	blank := "  "
+
+	tests := []struct {
+		name       string
+		transition string
+		reason     *string
+		want       error
+	}{
+		{"unknown transition", "archive", nil, ErrUnknownTransition},
+		{"missing reason", "cancel", nil, ErrReasonRequired},
+		{"blank reason", "reopen", &blank, ErrReasonRequired},
+	}
+
+	for _, tt := range tests {
+		_, err := s.TransitionProject(context.Background(), user, uuid.New(), tt.transition, tt.reason, nil)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
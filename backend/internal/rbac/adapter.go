@@ -0,0 +1,125 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	"project-management-backend/internal/db"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// PostgresAdapter stores casbin policies in the casbin_rule table.
+type PostgresAdapter struct {
+	db *db.Database
+}
+
+func NewPostgresAdapter(database *db.Database) *PostgresAdapter {
+	return &PostgresAdapter{db: database}
+}
+
+// LoadPolicy loads all policy rules from the database.
+func (a *PostgresAdapter) LoadPolicy(m model.Model) error {
+	rows, err := a.db.Pool.Query(context.Background(), `
+		SELECT ptype, COALESCE(v0, ''), COALESCE(v1, ''), COALESCE(v2, ''),
+		       COALESCE(v3, ''), COALESCE(v4, ''), COALESCE(v5, '')
+		FROM casbin_rule
+		ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to load policy: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rule := make([]string, 7)
+		if err := rows.Scan(&rule[0], &rule[1], &rule[2], &rule[3], &rule[4], &rule[5], &rule[6]); err != nil {
+			return fmt.Errorf("failed to scan policy: %w", err)
+		}
+
+		// Drop unused trailing columns so the rule matches the model arity
+		for len(rule) > 1 && rule[len(rule)-1] == "" {
+			rule = rule[:len(rule)-1]
+		}
+
+		persist.LoadPolicyArray(rule, m)
+	}
+
+	return rows.Err()
+}
+
+// SavePolicy replaces every stored rule with the policy held by the model.
+func (a *PostgresAdapter) SavePolicy(m model.Model) error {
+	ctx := context.Background()
+	tx, err := a.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM casbin_rule"); err != nil {
+		return fmt.Errorf("failed to clear policy: %w", err)
+	}
+
+	for _, sec := range []string{"p", "g"} {
+		for ptype, assertion := range m[sec] {
+			for _, rule := range assertion.Policy {
+				if _, err := tx.Exec(ctx, insertRuleSQL, ruleArgs(ptype, rule)...); err != nil {
+					return fmt.Errorf("failed to save policy rule: %w", err)
+				}
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// AddPolicy adds a policy rule to the database.
+func (a *PostgresAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	if _, err := a.db.Pool.Exec(context.Background(), insertRuleSQL, ruleArgs(ptype, rule)...); err != nil {
+		return fmt.Errorf("failed to add policy rule: %w", err)
+	}
+	return nil
+}
+
+// RemovePolicy removes a policy rule from the database.
+func (a *PostgresAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	return a.RemoveFilteredPolicy(sec, ptype, 0, rule...)
+}
+
+// RemoveFilteredPolicy removes the policy rules that match the filter. Empty
+// field values match anything.
+func (a *PostgresAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	query := "DELETE FROM casbin_rule WHERE ptype = $1"
+	args := []interface{}{ptype}
+	for i, value := range fieldValues {
+		column := fieldIndex + i
+		if value == "" || column > 5 {
+			continue
+		}
+		args = append(args, value)
+		query += fmt.Sprintf(" AND v%d = $%d", column, len(args))
+	}
+
+	if _, err := a.db.Pool.Exec(context.Background(), query, args...); err != nil {
+		return fmt.Errorf("failed to remove policy rules: %w", err)
+	}
+	return nil
+}
+
+const insertRuleSQL = `
+	INSERT INTO casbin_rule (ptype, v0, v1, v2, v3, v4, v5)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+func ruleArgs(ptype string, rule []string) []interface{} {
+	args := []interface{}{ptype}
+	for i := 0; i < 6; i++ {
+		if i < len(rule) {
+			args = append(args, rule[i])
+		} else {
+			args = append(args, nil)
+		}
+	}
+	return args
+}
+
@@ -0,0 +1,52 @@
+package rbac
+
+import (
+	"fmt"
+
+	"project-management-backend/internal/config"
+	"project-management-backend/internal/db"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+	"go.uber.org/zap"
+)
+
+const (
+	PolicySourceFile     = "file"
+	PolicySourceDatabase = "database"
+)
+
+// NewEnforcer builds a casbin enforcer from the configured model and loads
+// policies from either the policy file or the casbin_rule table. When a
+// reload interval is configured, policies are re-read in the background so
+// changes apply without a restart.
+func NewEnforcer(cfg *config.Config, database *db.Database, logger *zap.Logger) (*casbin.SyncedEnforcer, error) {
+	var adapter persist.Adapter
+	switch cfg.RBAC.PolicySource {
+	case PolicySourceDatabase:
+		adapter = NewPostgresAdapter(database)
+	case PolicySourceFile, "":
+		adapter = fileadapter.NewAdapter(cfg.RBAC.PolicyPath)
+	default:
+		return nil, fmt.Errorf("unknown RBAC policy source %q", cfg.RBAC.PolicySource)
+	}
+
+	enforcer, err := casbin.NewSyncedEnforcer(cfg.RBAC.ModelPath, adapter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create enforcer: %w", err)
+	}
+
+	if cfg.RBAC.ReloadInterval > 0 {
+		enforcer.StartAutoLoadPolicy(cfg.RBAC.ReloadInterval)
+	}
+
+	logger.Info("RBAC enforcer initialized",
+		zap.String("model_path", cfg.RBAC.ModelPath),
+		zap.String("policy_source", cfg.RBAC.PolicySource),
+		zap.Duration("reload_interval", cfg.RBAC.ReloadInterval),
+	)
+
+	return enforcer, nil
+}
+
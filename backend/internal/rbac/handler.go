@@ -0,0 +1,43 @@
+package rbac
+
+import (
+	"net/http"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	enforcer *casbin.SyncedEnforcer
+	logger   *zap.Logger
+}
+
+func NewHandler(enforcer *casbin.SyncedEnforcer, logger *zap.Logger) *Handler {
+	return &Handler{
+		enforcer: enforcer,
+		logger:   logger,
+	}
+}
+
+// @Summary Reload RBAC policy
+// @Description Re-read authorization policies from the configured source
+// @Tags rbac
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /rbac/reload [post]
+func (h *Handler) ReloadPolicy(c *gin.Context) {
+	if err := h.enforcer.LoadPolicy(); err != nil {
+		h.logger.Error("Failed to reload RBAC policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload policy"})
+		return
+	}
+
+	h.logger.Info("RBAC policy reloaded", zap.String("reloaded_by", c.GetString("user_id")))
+	c.JSON(http.StatusOK, gin.H{"message": "Policy reloaded"})
+}
+
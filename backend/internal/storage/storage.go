@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"project-management-backend/internal/config"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("invalid blob key")
+)
+
+// BlobStore keeps file contents by key. Keys are slash-separated relative
+// paths such as "projects/<id>/<attachment id>".
+type BlobStore interface {
+	// Put stores size bytes read from body under key, replacing any
+	// existing blob
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the blob's contents, or ErrBlobNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Presigner is implemented by stores that can hand out links for
+// downloading a blob directly from the store
+type Presigner interface {
+	PresignGet(key, filename, contentType string, expiry time.Duration) (string, error)
+}
+
+// New returns the blob store selected by the configuration
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocalStore(cfg.LocalPath)
+	case "s3":
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+func validKey(key string) bool {
+	return key != "" && path.Clean("/"+key) == "/"+key
+}
@@ -0,0 +1,30 @@
+package storage
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"projects/1/2", true},
+		{"file.txt", true},
+		{"a/b.c/d", true},
+		{"", false},
+		{"/projects/1", false},
+		{"projects/1/", false},
+		{"projects//1", false},
+		{"projects/../1", false},
+		{"../etc/passwd", false},
+		{"..", false},
+		{".", false},
+		{"projects/./1", false},
+	}
+
+	for _, tt := range tests {
+		if got := validKey(tt.key); got != tt.want {
+			t.Errorf("validKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
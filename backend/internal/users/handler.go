@@ -0,0 +1,256 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"project-management-backend/internal/api"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+	"project-management-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List users
+// @Description Search and page through user accounts by username (admin only)
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search by username or email"
+// @Param role query string false "Filter by role"
+// @Param is_active query bool false "Filter by active state"
+// @Param limit query int false "Number of users to return" default(50)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_total query bool false "Include the total number of matching users"
+// @Success 200 {object} pagination.Page[models.User]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := &ListUsersFilter{
+		Query: c.Query("q"),
+	}
+
+	if roleStr := c.Query("role"); roleStr != "" {
+		role := models.Role(roleStr)
+		if !role.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+			return
+		}
+		filter.Role = &role
+	}
+
+	if activeStr := c.Query("is_active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid is_active value"})
+			return
+		}
+		filter.IsActive = &active
+	}
+
+	page, err := h.service.ListUsers(c.Request.Context(), filter, params)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to list users", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Get a user
+// @Description Get user account details by ID (admin only)
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Update a user
+// @Description Update username, email or role. Roles can only be granted below your own; changing the role signs the user out everywhere.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body models.UpdateUserRequest true "User update data"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id} [put]
+func (h *Handler) UpdateUser(c *gin.Context) {
+	actor, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	if req.Role != nil && !req.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	user, err := h.service.UpdateUser(c.Request.Context(), actor, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to update user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Deactivate a user
+// @Description Block a user from logging in and revoke their sessions
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/deactivate [post]
+func (h *Handler) DeactivateUser(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+// @Summary Activate a user
+// @Description Re-enable a deactivated user account
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/activate [post]
+func (h *Handler) ActivateUser(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+// @Summary Delete a user
+// @Description Permanently delete a user account
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	actor, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.service.DeleteUser(c.Request.Context(), actor, id); err != nil {
+		h.respondError(c, "Failed to delete user", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) setActive(c *gin.Context, active bool) {
+	actor, ok := api.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := h.service.SetUserActive(c.Request.Context(), actor, id, active)
+	if err != nil {
+		h.respondError(c, "Failed to change user state", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) respondError(c *gin.Context, msg string, err error) {
+	api.RespondError(c, h.logger, msg, err,
+		api.ErrorResponse{Err: ErrUserNotFound, Status: http.StatusNotFound, Message: "User not found"},
+		api.ErrorResponse{Err: ErrForbidden, Status: http.StatusForbidden},
+		api.ErrorResponse{Err: ErrSelf, Status: http.StatusBadRequest},
+		api.ErrorResponse{Err: ErrConflict, Status: http.StatusConflict},
+	)
+}
+
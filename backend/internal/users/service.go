@@ -0,0 +1,314 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/db"
+	"project-management-backend/internal/models"
+	"project-management-backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrForbidden    = errors.New("insufficient permissions for this user")
+	ErrConflict     = errors.New("user with username or email already exists")
+	ErrSelf         = errors.New("cannot perform this action on your own account")
+)
+
+type Service struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+type ListUsersFilter struct {
+	Query    string
+	Role     *models.Role
+	IsActive *bool
+}
+
+func NewService(database *db.Database, logger *zap.Logger) *Service {
+	return &Service{
+		db:     database,
+		logger: logger,
+	}
+}
+
+const userColumns = `id, username, email, role, is_active, deactivated_at,
+		       suspended_until, suspension_reason, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.IsActive, &user.DeactivatedAt,
+		&user.SuspendedUntil, &user.SuspensionReason, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListUsers returns one page of the users matching the filter, ordered by
+// username. The list pages by offset.
+func (s *Service) ListUsers(ctx context.Context, filter *ListUsersFilter, params *pagination.Params) (*pagination.Page[*models.User], error) {
+	if params.Cursor != nil && params.Cursor.CreatedAt != nil {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		conditions = append(conditions, fmt.Sprintf("(username ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != nil {
+		args = append(args, *filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+	filterArgs := args
+
+	args = append(args, params.Fetch(), params.Offset())
+	rows, err := s.db.Pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM users
+		WHERE %s
+		ORDER BY username ASC
+		LIMIT $%d OFFSET $%d`,
+		userColumns, where, len(args)-1, len(args)),
+		args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	page := pagination.NewPage(users, params, pagination.OffsetCursor[*models.User](params))
+
+	if params.IncludeTotal {
+		var total int64
+		if err := s.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE "+where, filterArgs...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count users: %w", err)
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := scanUser(s.db.Pool.QueryRow(ctx,
+		"SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateUser applies an admin edit. Role changes must respect the hierarchy:
+// the actor must outrank both the user's current role and the role granted,
+// except that superusers may also manage and grant superuser.
+// A role change signs the user out everywhere, since session tokens carry
+// the role.
+func (s *Service) UpdateUser(ctx context.Context, actor *models.User, id uuid.UUID, req *models.UpdateUserRequest) (*models.User, error) {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID != actor.ID && !actor.CanManageRole(user.Role) {
+		return nil, ErrForbidden
+	}
+
+	roleChanged := false
+	if req.Role != nil && *req.Role != user.Role {
+		if user.ID == actor.ID {
+			return nil, ErrSelf
+		}
+		if !actor.CanAssignRole(*req.Role) {
+			return nil, ErrForbidden
+		}
+		user.Role = *req.Role
+		roleChanged = true
+	}
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+
+	var existingID uuid.UUID
+	err = s.db.Pool.QueryRow(ctx,
+		"SELECT id FROM users WHERE (username = $1 OR email = $2) AND id <> $3",
+		user.Username, user.Email, user.ID).Scan(&existingID)
+	switch {
+	case err == nil:
+		return nil, ErrConflict
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("failed to check for duplicate user: %w", err)
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	user.UpdatedAt = time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE users SET username = $2, email = $3, role = $4, updated_at = $5
+		WHERE id = $1`,
+		user.ID, user.Username, user.Email, user.Role, user.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// A role switch made under the old role no longer applies, and tokens
+	// issued with the old role must not outlive it
+	if roleChanged {
+		if _, err := tx.Exec(ctx,
+			"UPDATE role_inheritance SET is_active = FALSE WHERE user_id = $1 AND is_active",
+			user.ID); err != nil {
+			return nil, fmt.Errorf("failed to end role switch: %w", err)
+		}
+		if err := revokeSessions(ctx, tx, user.ID, user.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit user update: %w", err)
+	}
+
+	s.logger.Info("User updated",
+		zap.String("user_id", user.ID.String()),
+		zap.String("updated_by", actor.ID.String()),
+		zap.String("role", string(user.Role)))
+
+	return user, nil
+}
+
+// SetUserActive deactivates or reactivates an account. Deactivation also
+// revokes every session and refresh token of the user.
+func (s *Service) SetUserActive(ctx context.Context, actor *models.User, id uuid.UUID, active bool) (*models.User, error) {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == actor.ID {
+		return nil, ErrSelf
+	}
+	if !actor.CanManageRole(user.Role) {
+		return nil, ErrForbidden
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	var deactivatedAt *time.Time
+	if !active {
+		deactivatedAt = &now
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE users SET is_active = $2, deactivated_at = $3, updated_at = $4 WHERE id = $1",
+		user.ID, active, deactivatedAt, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if !active {
+		if err := revokeSessions(ctx, tx, user.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit user update: %w", err)
+	}
+
+	user.IsActive = &active
+	user.DeactivatedAt = deactivatedAt
+	user.UpdatedAt = now
+
+	s.logger.Info("User active state changed",
+		zap.String("user_id", user.ID.String()),
+		zap.String("updated_by", actor.ID.String()),
+		zap.Bool("is_active", active))
+
+	return user, nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, actor *models.User, id uuid.UUID) error {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == actor.ID {
+		return ErrSelf
+	}
+	if !actor.CanManageRole(user.Role) {
+		return ErrForbidden
+	}
+
+	result, err := s.db.Pool.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	s.logger.Info("User deleted",
+		zap.String("user_id", id.String()),
+		zap.String("deleted_by", actor.ID.String()))
+	return nil
+}
+
+// revokeSessions revokes every session and refresh token of the user
+func revokeSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, now time.Time) error {
+	if _, err := tx.Exec(ctx,
+		"UPDATE user_sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL",
+		userID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL",
+		userID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
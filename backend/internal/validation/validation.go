@@ -0,0 +1,300 @@
+// Package validation enforces the `validate` struct tags on request models
+// and reports failures as a list of per-field errors.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// FieldError describes one failed rule. Field uses the JSON name of the
+// field, with dots for nested fields.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors is the error returned when validation fails
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		if fieldErr.Field == "" {
+			messages = append(messages, fieldErr.Message)
+			continue
+		}
+		messages = append(messages, fieldErr.Field+" "+fieldErr.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+
+	// Cross-field rules that cannot be expressed as tags
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(models.CreateProjectRequest)
+		checkDateOrder(sl, req.StartDate, req.EndDate)
+		checkCoordinates(sl, req.Latitude, req.Longitude)
+		if len(req.Owners) > 0 {
+			checkOwnerShares(sl, req.Owners)
+		}
+	}, models.CreateProjectRequest{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(models.UpdateProjectRequest)
+		checkDateOrder(sl, req.StartDate, req.EndDate)
+	}, models.UpdateProjectRequest{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		checkOwnerShares(sl, sl.Current().Interface().(models.SetProjectOwnersRequest).Owners)
+	}, models.SetProjectOwnersRequest{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(models.CreateHouseRequest)
+		if req.BuildingID != nil {
+			checkUnitFields(sl, req)
+		}
+		if len(req.Owners) > 0 {
+			checkOwnerShares(sl, req.Owners)
+		}
+	}, models.CreateHouseRequest{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		checkOwnerShares(sl, sl.Current().Interface().(models.SetHouseOwnersRequest).Owners)
+	}, models.SetHouseOwnersRequest{})
+
+	return v
+}
+
+func checkDateOrder(sl validator.StructLevel, startDate, endDate *time.Time) {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		sl.ReportError(endDate, "end_date", "EndDate", "gtefield", "start_date")
+	}
+}
+
+// checkCoordinates requires latitude and longitude to be given together
+func checkCoordinates(sl validator.StructLevel, latitude, longitude *float64) {
+	if latitude != nil && longitude == nil {
+		sl.ReportError(longitude, "longitude", "Longitude", "required_with", "latitude")
+	}
+	if longitude != nil && latitude == nil {
+		sl.ReportError(latitude, "latitude", "Latitude", "required_with", "longitude")
+	}
+}
+
+// checkOwnerShares requires each owner at most once and percentages that
+// add up to exactly 100, compared in hundredths as they are stored
+func checkOwnerShares(sl validator.StructLevel, shares []models.OwnerShareInput) {
+	seen := make(map[uuid.UUID]bool, len(shares))
+	var total int64
+	for _, share := range shares {
+		if seen[share.OwnerID] {
+			sl.ReportError(shares, "owners", "Owners", "unique", "owner_id")
+			return
+		}
+		seen[share.OwnerID] = true
+		total += int64(math.Round(share.Percentage * 100))
+	}
+	if total != models.OwnershipTotal*100 {
+		sl.ReportError(shares, "owners", "Owners", "sum", strconv.Itoa(models.OwnershipTotal))
+	}
+}
+
+// checkUnitFields requires the unit number, category and size of a house
+// that is created as a unit of a building
+func checkUnitFields(sl validator.StructLevel, req models.CreateHouseRequest) {
+	if req.UnitNumber == nil {
+		sl.ReportError(req.UnitNumber, "unit_number", "UnitNumber", "required", "")
+	}
+	if req.CategoryType == nil {
+		sl.ReportError(req.CategoryType, "category_type", "CategoryType", "required", "")
+	}
+	if req.SizeSqm == nil {
+		sl.ReportError(req.SizeSqm, "size_sqm", "SizeSqm", "required", "")
+	}
+}
+
+// Struct validates v against its `validate` tags and registered
+// cross-field rules. It returns nil or an Errors value.
+func Struct(v interface{}) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrs := make(Errors, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   fieldName(fieldErr),
+			Rule:    fieldErr.Tag(),
+			Message: message(fieldErr),
+		})
+	}
+	return fieldErrs
+}
+
+// BindJSON decodes the request body into obj and validates it. On failure
+// it writes a 400 response listing the field errors and returns false.
+func BindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		Respond(c, http.StatusBadRequest, decodeErrors(err))
+		return false
+	}
+
+	if err := Struct(obj); err != nil {
+		var fieldErrs Errors
+		if !errors.As(err, &fieldErrs) {
+			fieldErrs = Errors{{Rule: "invalid", Message: err.Error()}}
+		}
+		Respond(c, http.StatusBadRequest, fieldErrs)
+		return false
+	}
+
+	return true
+}
+
+// Respond writes a validation failure response
+func Respond(c *gin.Context, status int, fieldErrs Errors) {
+	c.JSON(status, gin.H{
+		"error":  "Invalid request data",
+		"fields": fieldErrs,
+	})
+}
+
+// decodeErrors turns a JSON decoding failure into field errors, naming the
+// field where encoding/json reports one
+func decodeErrors(err error) Errors {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return Errors{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonType(typeErr.Type)),
+		}}
+	}
+
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return Errors{{Rule: "type", Message: fmt.Sprintf("invalid timestamp %s, expected RFC 3339", timeErr.Value)}}
+	}
+
+	return Errors{{Rule: "json", Message: "request body must be valid JSON"}}
+}
+
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// fieldName strips the struct name from the error namespace, leaving the
+// JSON path of the field
+func fieldName(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fieldErr.Field()
+}
+
+func message(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	isString := fieldErr.Kind() == reflect.String
+	isList := fieldErr.Kind() == reflect.Slice || fieldErr.Kind() == reflect.Map
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters", param)
+		}
+		if isList {
+			return fmt.Sprintf("must have at least %s entries", param)
+		}
+		return fmt.Sprintf("must be at least %s", param)
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters", param)
+		}
+		if isList {
+			return fmt.Sprintf("must have at most %s entries", param)
+		}
+		return fmt.Sprintf("must be at most %s", param)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "required_with":
+		return "is required with " + param
+	case "gtefield":
+		return "must not be before " + param
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be at least " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be at most " + param
+	case "unique":
+		return "must not repeat " + param
+	case "sum":
+		return "percentages must add up to " + param
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
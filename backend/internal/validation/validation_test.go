@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"project-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type testItem struct {
+	Label string `json:"label" validate:"required"`
+}
+
+type testRequest struct {
+	Name   string     `json:"name" validate:"required,notblank,max=5"`
+	Email  string     `json:"email,omitempty" validate:"omitempty,email"`
+	Kind   string     `json:"kind,omitempty" validate:"omitempty,oneof=a b"`
+	Count  int        `json:"count" validate:"gte=1"`
+	Tags   []string   `json:"tags" validate:"max=2"`
+	Items  []testItem `json:"items" validate:"dive"`
+	Hidden string     `json:"-"`
+	NoTag  string     `validate:"max=1"`
+}
+
+func validRequest() testRequest {
+	return testRequest{Name: "ok", Count: 1}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*testRequest)
+		want   Errors
+	}{
+		{"valid", func(r *testRequest) {}, nil},
+		{"required", func(r *testRequest) { r.Name = "" },
+			Errors{{"name", "required", "is required"}}},
+		{"blank", func(r *testRequest) { r.Name = "   " },
+			Errors{{"name", "notblank", "must not be blank"}}},
+		{"string max", func(r *testRequest) { r.Name = "toolong" },
+			Errors{{"name", "max", "must be at most 5 characters"}}},
+		{"email", func(r *testRequest) { r.Email = "nope" },
+			Errors{{"email", "email", "must be a valid email address"}}},
+		{"oneof", func(r *testRequest) { r.Kind = "c" },
+			Errors{{"kind", "oneof", "must be one of: a, b"}}},
+		{"number", func(r *testRequest) { r.Count = 0 },
+			Errors{{"count", "gte", "must be at least 1"}}},
+		{"list max", func(r *testRequest) { r.Tags = []string{"x", "y", "z"} },
+			Errors{{"tags", "max", "must have at most 2 entries"}}},
+		{"nested", func(r *testRequest) { r.Items = []testItem{{"a"}, {""}} },
+			Errors{{"items[1].label", "required", "is required"}}},
+		{"field without json tag", func(r *testRequest) { r.NoTag = "xy" },
+			Errors{{"NoTag", "max", "must be at most 1 characters"}}},
+		{"several", func(r *testRequest) { r.Name = ""; r.Count = 0 },
+			Errors{{"name", "required", "is required"}, {"count", "gte", "must be at least 1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+
+			err := Struct(req)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Struct: %v", err)
+				}
+				return
+			}
+
+			var fieldErrs Errors
+			if !errors.As(err, &fieldErrs) {
+				t.Fatalf("Struct error = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(fieldErrs, tt.want) {
+				t.Errorf("Struct = %+v, want %+v", fieldErrs, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructCrossFieldRules(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+	latitude := 10.0
+	ownerA, ownerB := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name  string
+		req   interface{}
+		field string
+		rule  string
+	}{
+		{"end before start", models.CreateProjectRequest{Name: "p", StartDate: &start, EndDate: &before}, "end_date", "gtefield"},
+		{"update end before start", models.UpdateProjectRequest{StartDate: &start, EndDate: &before}, "end_date", "gtefield"},
+		{"latitude without longitude", models.CreateProjectRequest{Name: "p", Latitude: &latitude}, "longitude", "required_with"},
+		{"shares below total", models.CreateProjectRequest{Name: "p", Owners: []models.OwnerShareInput{
+			{OwnerID: ownerA, Percentage: 60}, {OwnerID: ownerB, Percentage: 39.99},
+		}}, "owners", "sum"},
+		{"repeated owner", models.SetProjectOwnersRequest{Owners: []models.OwnerShareInput{
+			{OwnerID: ownerA, Percentage: 50}, {OwnerID: ownerA, Percentage: 50},
+		}}, "owners", "unique"},
+		{"unit without fields", models.CreateHouseRequest{Name: "h", BuildingID: &ownerA}, "unit_number", "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldErrs Errors
+			if err := Struct(tt.req); !errors.As(err, &fieldErrs) {
+				t.Fatalf("Struct error = %v, want Errors", err)
+			}
+			for _, fieldErr := range fieldErrs {
+				if fieldErr.Field == tt.field && fieldErr.Rule == tt.rule {
+					return
+				}
+			}
+			t.Errorf("Struct = %+v, want a %s error on %s", fieldErrs, tt.rule, tt.field)
+		})
+	}
+
+	shares := models.SetProjectOwnersRequest{Owners: []models.OwnerShareInput{
+		{OwnerID: ownerA, Percentage: 33.33}, {OwnerID: ownerB, Percentage: 66.67},
+	}}
+	if err := Struct(shares); err != nil {
+		t.Errorf("fractional shares adding up to 100: %v", err)
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	err := Errors{{Field: "name", Message: "is required"}, {Message: "body is empty"}}
+	if got := err.Error(); got != "validation failed: name is required; body is empty" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		ok     bool
+		field  string
+		rule   string
+		substr string
+	}{
+		{"valid", `{"name":"ok","count":2}`, true, "", "", ""},
+		{"invalid json", `{"name":`, false, "", "json", "valid JSON"},
+		{"wrong type", `{"name":"ok","count":"two"}`, false, "count", "type", "must be of type number"},
+		{"failed rule", `{"name":"","count":2}`, false, "name", "required", "is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var req testRequest
+			if ok := BindJSON(c, &req); ok != tt.ok {
+				t.Fatalf("BindJSON = %v, want %v (%s)", ok, tt.ok, w.Body)
+			}
+			if tt.ok {
+				return
+			}
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			var body struct {
+				Fields Errors `json:"fields"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Fields) != 1 {
+				t.Fatalf("response = %s", w.Body)
+			}
+			got := body.Fields[0]
+			if got.Field != tt.field || got.Rule != tt.rule || !strings.Contains(got.Message, tt.substr) {
+				t.Errorf("field error = %+v, want %s %s %q", got, tt.field, tt.rule, tt.substr)
+			}
+		})
+	}
+}
+
@@ -1,6 +1,7 @@
 import React, { useState } from 'react';
 import { useQuery, useMutation, useQueryClient } from 'react-query';
 import { apiClient } from '../utils/api';
+import { Page } from '../types';
 import { 
   Users, UserPlus, Crown, Shield, UserCheck, User, 
   Edit3, Trash2, Mail, X, Save
@@ -38,8 +39,8 @@ export const OrganizationManagement: React.FC<OrganizationManagementProps> = ({
   const { data: members, isLoading } = useQuery<OrganizationMember[]>(
     ['organization', organizationId, 'members'],
     async () => {
-      const response = await apiClient.get(`/organizations/${organizationId}/members`) as OrganizationMember[];
-      return response;
+      const response = await apiClient.get(`/organizations/${organizationId}/members?limit=100`) as Page<OrganizationMember>;
+      return response.items;
     },
     {
       enabled: ['owner', 'admin'].includes(userRole),
@@ -1,6 +1,6 @@
 import { useQuery, useMutation, useQueryClient } from 'react-query';
 import { apiClient } from '../utils/api';
-import { Project } from '../types';
+import { Page, Project, ProjectAnalytics, ProjectTransition } from '../types';
 import { ProjectFilters } from '../store/organizationStore';
 
 export const useProjects = (filters?: ProjectFilters) => {
@@ -20,7 +20,17 @@ export const useProjects = (filters?: ProjectFilters) => {
         });
       }
       
-      const response = await apiClient.get<Project[]>(`/api/projects?${params.toString()}`);
+      const response = await apiClient.get<Page<Project>>(`/api/projects?${params.toString()}`);
+      return response.items;
+    }
+  );
+};
+
+export const useProjectAnalytics = () => {
+  return useQuery<ProjectAnalytics>(
+    ['projects', 'analytics'],
+    async () => {
+      const response = await apiClient.get<ProjectAnalytics>('/api/projects/analytics');
       return response;
     }
   );
@@ -72,6 +82,41 @@ export const useUpdateProject = () => {
   );
 };
 
+export const useProjectTransitions = (id: string) => {
+  return useQuery<ProjectTransition[]>(
+    ['project-transitions', id],
+    async () => {
+      const response = await apiClient.get<ProjectTransition[]>(`/api/projects/${id}/transitions`);
+      return response;
+    },
+    {
+      enabled: !!id,
+    }
+  );
+};
+
+export const useTransitionProject = () => {
+  const queryClient = useQueryClient();
+
+  return useMutation<Project, Error, { id: string; transition: string; reason?: string }>(
+    async ({ id, transition, reason }) => {
+      const response = await apiClient.post<Project>(
+        `/api/projects/${id}/transitions/${transition}`,
+        reason ? { reason } : undefined
+      );
+      return response;
+    },
+    {
+      onSuccess: (_, { id }) => {
+        queryClient.invalidateQueries(['projects']);
+        queryClient.invalidateQueries(['project', id]);
+        queryClient.invalidateQueries(['project-transitions', id]);
+        queryClient.invalidateQueries(['project-versions', id]);
+      },
+    }
+  );
+};
+
 export const useDeleteProject = () => {
   const queryClient = useQueryClient();
 
@@ -91,8 +136,8 @@ export const useProjectVersions = (id: string) => {
   return useQuery<any[]>(
     ['project-versions', id],
     async () => {
-      const response = await apiClient.get<any[]>(`/api/projects/${id}/versions`);
-      return response;
+      const response = await apiClient.get<Page<any>>(`/api/projects/${id}/versions?limit=100`);
+      return response.items;
     },
     {
       enabled: !!id,
@@ -1,13 +1,13 @@
 import { useQuery, useMutation, useQueryClient } from 'react-query';
 import { apiClient } from '../utils/api';
-import { ApiToken, CreateTokenRequest } from '../types';
+import { ApiToken, CreateTokenRequest, Page } from '../types';
 
 export const useApiTokens = () => {
   return useQuery<ApiToken[]>(
     ['apiTokens'],
     async () => {
-      const response = await apiClient.get<ApiToken[]>('/api/auth/tokens');
-      return response;
+      const response = await apiClient.get<Page<ApiToken>>('/api/auth/tokens');
+      return response.items;
     }
   );
 };
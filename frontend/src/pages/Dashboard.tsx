@@ -1,16 +1,17 @@
-import { useProjects } from '../hooks/useProjects';
+import { useProjects, useProjectAnalytics } from '../hooks/useProjects';
 import { useAuthStore } from '../store/authStore';
 import { UserAvatar } from '../components/UserAvatar';
 import { FolderOpen, DollarSign, MapPin, TrendingUp, Clock, CheckCircle } from 'lucide-react';
 
 const Dashboard = () => {
   const { data: projects, isLoading } = useProjects();
+  const { data: analytics } = useProjectAnalytics();
   const { user } = useAuthStore();
 
   const stats = [
     {
       name: 'Total Projects',
-      value: projects?.length || 0,
+      value: analytics?.total_projects || 0,
       icon: FolderOpen,
       color: 'text-blue-600',
       bgColor: 'bg-gradient-to-br from-blue-50 to-blue-100',
@@ -21,7 +22,7 @@ const Dashboard = () => {
     {
       name: 'Total Budget',
       value: (() => {
-        const totalBudget = projects?.reduce((sum, p) => sum + (p.budget || 0), 0) || 0;
+        const totalBudget = analytics?.total_budget || 0;
         const millions = totalBudget / 1000000;
         return millions >= 1 ? `$${millions.toFixed(1)}M` : `$${totalBudget.toLocaleString()}`;
       })(),
@@ -34,7 +35,7 @@ const Dashboard = () => {
     },
     {
       name: 'Active Projects',
-      value: projects?.filter(p => p.status === 'active').length || 0,
+      value: analytics?.by_status.find(group => group.key === 'active')?.count || 0,
       icon: TrendingUp,
       color: 'text-amber-600',
       bgColor: 'bg-gradient-to-br from-amber-50 to-amber-100',
@@ -44,7 +45,7 @@ const Dashboard = () => {
     },
     {
       name: 'Locations',
-      value: new Set(projects?.map(p => p.city).filter(Boolean)).size || 0,
+      value: analytics?.by_city.filter(group => group.key).length || 0,
       icon: MapPin,
       color: 'text-purple-600',
       bgColor: 'bg-gradient-to-br from-purple-50 to-purple-100',
@@ -1,8 +1,8 @@
 import { useState } from 'react';
 import { useParams, Link } from 'react-router-dom';
-import { useProject, useUpdateProject, useProjectVersions } from '../hooks/useProjects';
+import { useProject, useUpdateProject, useProjectVersions, useProjectTransitions, useTransitionProject } from '../hooks/useProjects';
 import { useAuthStore } from '../store/authStore';
-import { Project } from '../types';
+import { Project, ProjectTransition } from '../types';
 import { 
   ArrowLeft, Edit, Save, X, Calendar, MapPin, Building2, 
   Folder, DollarSign, Clock, CheckCircle, AlertCircle, 
@@ -17,11 +17,15 @@ const ProjectDetail = () => {
   const { data: project, isLoading } = useProject(id!);
   const { data: versions, isLoading: versionsLoading } = useProjectVersions(id!);
   const { hasRole } = useAuthStore();
+  const { data: transitions } = useProjectTransitions(id!);
   const updateProjectMutation = useUpdateProject();
+  const transitionMutation = useTransitionProject();
   const [isEditing, setIsEditing] = useState(false);
   const [editData, setEditData] = useState<Partial<Project>>({});
   const [showVersions, setShowVersions] = useState(false);
   const [viewingVersion, setViewingVersion] = useState<any>(null);
+  const [pendingTransition, setPendingTransition] = useState<ProjectTransition | null>(null);
+  const [transitionReason, setTransitionReason] = useState('');
 
   const canEdit = hasRole('localadmin');
 
@@ -48,6 +52,32 @@ const ProjectDetail = () => {
     setEditData({});
   };
 
+  const runTransition = async (transition: ProjectTransition, reason?: string) => {
+    if (!id) return;
+
+    try {
+      await transitionMutation.mutateAsync({ id, transition: transition.name, reason });
+      setPendingTransition(null);
+      setTransitionReason('');
+    } catch (error) {
+      console.error('Failed to change project status:', error);
+    }
+  };
+
+  const handleTransition = (transition: ProjectTransition) => {
+    if (transition.requires_reason) {
+      setPendingTransition(transition);
+      setTransitionReason('');
+    } else {
+      runTransition(transition);
+    }
+  };
+
+  const handleCancelTransition = () => {
+    setPendingTransition(null);
+    setTransitionReason('');
+  };
+
   const handleViewVersion = (version: any) => {
     setViewingVersion(version);
   };
@@ -219,26 +249,54 @@ const ProjectDetail = () => {
             <div>
               <p className="text-sm font-medium text-gray-600">Status</p>
               <div className="mt-2">
-                {isEditing ? (
-                  <select
-                    value={currentData.status || ''}
-                    onChange={(e) => setEditData(prev => ({ ...prev, status: e.target.value }))}
-                    className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-emerald-500 focus:border-transparent"
-                  >
-                    <option value="">Select Status</option>
-                    <option value="planning">Planning</option>
-                    <option value="active">Active</option>
-                    <option value="completed">Completed</option>
-                    <option value="on-hold">On Hold</option>
-                    <option value="cancelled">Cancelled</option>
-                  </select>
-                ) : (
-                  <span className={`inline-flex items-center px-3 py-1 rounded-full text-sm font-medium border ${getStatusColor(project.status || 'unknown')}`}>
-                    {getStatusIcon(project.status || 'unknown')}
-                    {project.status || 'Unknown'}
-                  </span>
-                )}
+                {/* Status changes go through the workflow transitions, not the edit form */}
+                <span className={`inline-flex items-center px-3 py-1 rounded-full text-sm font-medium border ${getStatusColor(project.status || 'unknown')}`}>
+                  {getStatusIcon(project.status || 'unknown')}
+                  {project.status || 'Unknown'}
+                </span>
               </div>
+              {canEdit && transitions && transitions.length > 0 && !pendingTransition && (
+                <div className="mt-3 flex flex-wrap gap-2">
+                  {transitions.map((transition) => (
+                    <button
+                      key={transition.name}
+                      onClick={() => handleTransition(transition)}
+                      disabled={transitionMutation.isLoading}
+                      className="px-3 py-1 text-xs font-medium text-emerald-700 bg-emerald-50 hover:bg-emerald-100 border border-emerald-200 rounded-lg transition-colors disabled:opacity-50"
+                      title={`Move to ${transition.to}`}
+                    >
+                      {transition.name}
+                    </button>
+                  ))}
+                </div>
+              )}
+              {pendingTransition && (
+                <div className="mt-3 space-y-2">
+                  <textarea
+                    value={transitionReason}
+                    onChange={(e) => setTransitionReason(e.target.value)}
+                    rows={2}
+                    maxLength={1000}
+                    className="w-full px-3 py-2 text-sm border border-gray-300 rounded-lg focus:ring-2 focus:ring-emerald-500 focus:border-transparent"
+                    placeholder={`Reason to ${pendingTransition.name}`}
+                  />
+                  <div className="flex gap-2">
+                    <button
+                      onClick={() => runTransition(pendingTransition, transitionReason.trim())}
+                      disabled={!transitionReason.trim() || transitionMutation.isLoading}
+                      className="px-3 py-1 text-xs font-medium text-white bg-emerald-600 hover:bg-emerald-700 rounded-lg transition-colors disabled:opacity-50"
+                    >
+                      {transitionMutation.isLoading ? 'Saving...' : pendingTransition.name}
+                    </button>
+                    <button
+                      onClick={handleCancelTransition}
+                      className="px-3 py-1 text-xs font-medium text-gray-700 bg-gray-100 hover:bg-gray-200 rounded-lg transition-colors"
+                    >
+                      Cancel
+                    </button>
+                  </div>
+                </div>
+              )}
             </div>
             <div className="w-12 h-12 bg-emerald-100 rounded-xl flex items-center justify-center">
               <Settings className="w-6 h-6 text-emerald-600" />
@@ -1,5 +1,6 @@
 import { create } from 'zustand';
 import { apiClient } from '../utils/api';
+import { Page } from '../types';
 
 export interface Organization {
   id: string;
@@ -106,9 +107,10 @@ export const useOrganizationStore = create<OrganizationState>((set, get) => ({
       const params = new URLSearchParams();
       if (search) params.append('search', search);
       if (type) params.append('type', type);
+      params.append('limit', '100');
       
-      const response = await apiClient.get(`/organizations?${params.toString()}`);
-      set({ organizations: response as Organization[], loading: false });
+      const response = await apiClient.get(`/organizations?${params.toString()}`) as Page<Organization>;
+      set({ organizations: response.items, loading: false });
     } catch (error: any) {
       console.error('Error fetching organizations:', error);
       set({ 
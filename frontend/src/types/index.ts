@@ -74,6 +74,41 @@ export interface AuthResponse {
   expires_at: string;
 }
 
+export interface AnalyticsGroup {
+  key: string | null;
+  count: number;
+  total_budget: number;
+}
+
+export interface ProjectAnalytics {
+  total_projects: number;
+  total_budget: number;
+  by_status: AnalyticsGroup[];
+  by_city: AnalyticsGroup[];
+  by_state: AnalyticsGroup[];
+  by_start_month: AnalyticsGroup[];
+  average_duration_days: number | null;
+  overdue: {
+    count: number;
+    total_budget: number;
+    projects: { id: string; name: string; status?: string; end_date: string; days_overdue: number }[];
+  };
+  budget_buckets: { min: number | null; max: number | null; count: number; total_budget: number }[];
+}
+
+export interface ProjectTransition {
+  name: string;
+  from: string[];
+  to: string;
+  requires_reason: boolean;
+}
+
+export interface Page<T> {
+  items: T[];
+  next_cursor: string | null;
+  total?: number;
+}
+
 export interface ApiResponse<T = any> {
   data?: T;
   error?: string;
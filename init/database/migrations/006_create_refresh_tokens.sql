@@ -0,0 +1,24 @@
+-- Create refresh_tokens table for rotating refresh tokens
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for efficient queries
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Comments for documentation
+COMMENT ON TABLE refresh_tokens IS 'Rotating refresh tokens. Each login starts a new family; every refresh rotates to a new token in the same family.';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Shared by all tokens descended from one login. Reuse of a rotated token revokes the whole family.';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'SHA-256 hex digest of the refresh token. The raw token is never stored.';
+COMMENT ON COLUMN refresh_tokens.rotated_at IS 'When this token was exchanged for a new one (NULL if still current).';
+COMMENT ON COLUMN refresh_tokens.revoked_at IS 'When this token was revoked (NULL if not revoked).';
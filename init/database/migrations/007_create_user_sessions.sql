@@ -0,0 +1,21 @@
+-- Create user_sessions table for server-side session tracking and revocation
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes for efficient queries
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at ON user_sessions(expires_at);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_revoked_at ON user_sessions(revoked_at);
+
+-- Comments for documentation
+COMMENT ON TABLE user_sessions IS 'Server-side record of every login. The session ID is carried as the jti claim of session tokens and as the refresh token family ID.';
+COMMENT ON COLUMN user_sessions.last_seen_at IS 'Timestamp of the last authenticated request made with this session.';
+COMMENT ON COLUMN user_sessions.revoked_at IS 'When the session was logged out or revoked (NULL if still active).';
@@ -0,0 +1,9 @@
+-- The original UNIQUE(user_id, is_active) constraint also limits each user to a
+-- single inactive row, so a second switch/return cycle fails. Only the active
+-- row needs to be unique.
+ALTER TABLE role_inheritance DROP CONSTRAINT IF EXISTS role_inheritance_user_id_is_active_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_inheritance_one_active
+    ON role_inheritance(user_id) WHERE is_active;
+
+COMMENT ON INDEX idx_role_inheritance_one_active IS 'Ensures at most one active role inheritance per user while keeping full history.';
@@ -0,0 +1,6 @@
+-- Record the real actor behind impersonation sessions
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS actor_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_actor_id ON user_sessions(actor_id);
+
+COMMENT ON COLUMN user_sessions.actor_id IS 'Administrator impersonating user_id in this session (NULL for ordinary logins).';
@@ -0,0 +1,8 @@
+-- Allow administrators to deactivate accounts without deleting them
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
+
+COMMENT ON COLUMN users.is_active IS 'FALSE when an administrator has deactivated the account. Inactive users cannot log in.';
+COMMENT ON COLUMN users.deactivated_at IS 'When the account was deactivated (NULL if active).';
@@ -0,0 +1,34 @@
+-- Create casbin_rule table for database-backed RBAC policies
+-- (used when RBAC_POLICY_SOURCE=database)
+CREATE TABLE IF NOT EXISTS casbin_rule (
+    id SERIAL PRIMARY KEY,
+    ptype VARCHAR(10) NOT NULL,
+    v0 VARCHAR(256),
+    v1 VARCHAR(256),
+    v2 VARCHAR(256),
+    v3 VARCHAR(256),
+    v4 VARCHAR(256),
+    v5 VARCHAR(256)
+);
+
+CREATE INDEX IF NOT EXISTS idx_casbin_rule_ptype ON casbin_rule(ptype);
+
+-- Seed with the same defaults as backend/rbac_policy.csv
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'guest', 'projects', 'read'),
+    ('p', 'localadmin', 'projects', 'create'),
+    ('p', 'localadmin', 'projects/*', 'update'),
+    ('p', 'localadmin', 'projects/*', 'delete'),
+    ('p', 'localadmin', 'users', 'read'),
+    ('p', 'localadmin', 'users/*', 'read'),
+    ('p', 'localadmin', 'users/*', 'update'),
+    ('p', 'localadmin', 'users/*', 'delete'),
+    ('p', 'superuser', 'rbac', '*');
+
+INSERT INTO casbin_rule (ptype, v0, v1) VALUES
+    ('g', 'user', 'guest'),
+    ('g', 'localadmin', 'user'),
+    ('g', 'sysadmin', 'localadmin'),
+    ('g', 'superuser', 'sysadmin');
+
+COMMENT ON TABLE casbin_rule IS 'Casbin policy (p) and role inheritance (g) rules. Subjects are role names or user IDs.';
@@ -0,0 +1,64 @@
+-- Create project_members table for per-project roles of application users
+CREATE TABLE IF NOT EXISTS project_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'subadmin', 'user')),
+    added_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- A user holds a single role per project
+    UNIQUE(project_id, user_id)
+);
+
+-- Index for counting members by role and for authorization lookups
+CREATE INDEX IF NOT EXISTS idx_project_members_project_role ON project_members(project_id, role);
+CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members(user_id);
+
+-- Guardrail for the caps enforced by the application: at most 4 admins,
+-- 10 sub-admins and 100 users per project
+CREATE OR REPLACE FUNCTION fn_validate_project_member_counts() RETURNS TRIGGER AS $$
+DECLARE
+    member_count INT;
+    member_limit INT;
+BEGIN
+    member_limit := CASE NEW.role
+        WHEN 'admin' THEN 4
+        WHEN 'subadmin' THEN 10
+        ELSE 100
+    END;
+
+    SELECT COUNT(*) INTO member_count
+    FROM project_members
+    WHERE project_id = NEW.project_id AND role = NEW.role;
+
+    IF member_count > member_limit THEN
+        RAISE EXCEPTION 'project % cannot have more than % members with role % (found %)',
+            NEW.project_id, member_limit, NEW.role, member_count;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_validate_project_member_counts ON project_members;
+CREATE TRIGGER trg_validate_project_member_counts
+AFTER INSERT OR UPDATE OF role ON project_members
+FOR EACH ROW EXECUTE FUNCTION fn_validate_project_member_counts();
+
+-- Project-scoped policies. The middleware checks the caller's project role
+-- as the subject "project:<role>".
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'project:user', 'projects/*', 'read'),
+    ('p', 'project:subadmin', 'projects/*', 'update'),
+    ('p', 'project:admin', 'projects/*', 'manage'),
+    ('p', 'localadmin', 'projects/*', 'read'),
+    ('p', 'localadmin', 'projects/*', 'manage');
+
+INSERT INTO casbin_rule (ptype, v0, v1) VALUES
+    ('g', 'project:subadmin', 'project:user'),
+    ('g', 'project:admin', 'project:subadmin');
+
+COMMENT ON TABLE project_members IS 'Per-project roles of users. Capped at 4 admins, 10 sub-admins and 100 users per project.';
+COMMENT ON COLUMN project_members.added_by IS 'User who added the member to the project';
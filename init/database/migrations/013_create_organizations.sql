@@ -0,0 +1,95 @@
+-- Create organizations and organization membership tables
+-- (matches scripts/migrations/005_organizations_schema.sql where it has already run)
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    description TEXT,
+    type TEXT NOT NULL CHECK (type IN ('Company', 'Government', 'NGO', 'Individual', 'Partnership')),
+    address TEXT,
+    city TEXT,
+    state TEXT,
+    postal_code TEXT,
+    country TEXT DEFAULT 'US',
+    phone TEXT,
+    email TEXT,
+    website TEXT,
+    tax_id TEXT,
+    registration_number TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS organization_users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'manager', 'viewer', 'member')),
+    permissions JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(organization_id, user_id)
+);
+
+-- Projects belong to an organization
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
+
+-- Projects created before organizations existed were visible to every user
+-- and writable by localadmins. Move them into a default organization that
+-- every existing user joins with a matching role, so they keep that access:
+-- sysadmins and superusers own it, localadmins may write, everyone else
+-- views. Later users join it through invitations.
+INSERT INTO organizations (id, name, description, type)
+SELECT '00000000-0000-0000-0000-000000000001', 'Default Organization',
+       'Projects created before organizations were introduced', 'Company'
+WHERE EXISTS (SELECT 1 FROM projects WHERE organization_id IS NULL)
+ON CONFLICT (id) DO NOTHING;
+
+UPDATE projects SET organization_id = '00000000-0000-0000-0000-000000000001'
+WHERE organization_id IS NULL
+  AND EXISTS (SELECT 1 FROM organizations WHERE id = '00000000-0000-0000-0000-000000000001');
+
+INSERT INTO organization_users (organization_id, user_id, role)
+SELECT o.id, u.id,
+       CASE u.role
+           WHEN 'superuser' THEN 'owner'
+           WHEN 'sysadmin' THEN 'owner'
+           WHEN 'localadmin' THEN 'manager'
+           ELSE 'viewer'
+       END
+FROM organizations o
+CROSS JOIN users u
+WHERE o.id = '00000000-0000-0000-0000-000000000001'
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+-- Pending invitations to join an organization. Only the token hash is stored.
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'manager', 'viewer', 'member')),
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes for tenant scoping and membership lookups
+CREATE INDEX IF NOT EXISTS idx_projects_organization_id ON projects(organization_id);
+CREATE INDEX IF NOT EXISTS idx_organization_users_org_id ON organization_users(organization_id);
+CREATE INDEX IF NOT EXISTS idx_organization_users_user_id ON organization_users(user_id);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_id ON organization_invitations(organization_id);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_email ON organization_invitations(LOWER(email));
+
+-- Creating organizations is limited to localadmin and above
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'localadmin', 'organizations', 'create');
+
+COMMENT ON TABLE organization_invitations IS 'Invitations to join an organization, accepted by the user with the invited email';
+COMMENT ON COLUMN organization_invitations.token_hash IS 'SHA-256 hash of the invitation token';
+COMMENT ON COLUMN projects.organization_id IS 'Owning organization. Projects are only visible to members of this organization.';
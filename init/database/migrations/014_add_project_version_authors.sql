@@ -0,0 +1,14 @@
+-- Record who made each project version
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS updated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE project_versions ADD COLUMN IF NOT EXISTS updated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE project_versions ADD COLUMN IF NOT EXISTS archived_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Match the column sizes of projects so every row can be archived
+ALTER TABLE project_versions ALTER COLUMN name TYPE VARCHAR(255);
+ALTER TABLE project_versions ALTER COLUMN owner_name TYPE VARCHAR(255);
+
+UPDATE projects SET version = 1 WHERE version IS NULL;
+
+COMMENT ON COLUMN projects.updated_by IS 'User who wrote the current version';
+COMMENT ON COLUMN project_versions.updated_by IS 'User who wrote this version';
+COMMENT ON COLUMN project_versions.archived_by IS 'User whose change replaced this version';
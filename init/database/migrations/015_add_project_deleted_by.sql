@@ -0,0 +1,11 @@
+-- Record who moved a project to the trash (deleted_at is added in 003)
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Trash listings and the purge job only look at deleted rows
+CREATE INDEX IF NOT EXISTS idx_projects_trash ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Purging is limited to localadmin and above
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'localadmin', 'projects/*', 'purge');
+
+COMMENT ON COLUMN projects.deleted_by IS 'User who moved the project to the trash';
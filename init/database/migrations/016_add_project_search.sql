@@ -0,0 +1,21 @@
+-- Full-text search over the descriptive project columns. The 'simple'
+-- configuration avoids stemming names and street addresses.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple',
+            coalesce(name, '') || ' ' ||
+            coalesce(address, '') || ' ' ||
+            coalesce(city, '') || ' ' ||
+            coalesce(owner_name, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search ON projects USING GIN (search_vector);
+
+-- Common filter and sort columns for project listings
+CREATE INDEX IF NOT EXISTS idx_projects_city_lower ON projects (LOWER(city));
+CREATE INDEX IF NOT EXISTS idx_projects_state_lower ON projects (LOWER(state));
+CREATE INDEX IF NOT EXISTS idx_projects_budget ON projects (budget);
+CREATE INDEX IF NOT EXISTS idx_projects_start_date ON projects (start_date);
+CREATE INDEX IF NOT EXISTS idx_projects_end_date ON projects (end_date);
+
+COMMENT ON COLUMN projects.search_vector IS 'Generated tsvector over name, address, city and owner_name for ?q= search';
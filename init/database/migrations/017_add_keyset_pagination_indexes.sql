@@ -0,0 +1,3 @@
+-- Keyset pagination walks lists in (created_at DESC, id DESC) order
+CREATE INDEX IF NOT EXISTS idx_projects_created_at_id ON projects (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_created_at_id ON api_tokens (user_id, created_at DESC, id DESC);
@@ -0,0 +1,37 @@
+-- Status changes of projects, written on creation and by every workflow
+-- transition. Time in a status runs until the next entry.
+CREATE TABLE IF NOT EXISTS project_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    transition VARCHAR(50),
+    reason TEXT,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_status_history_project ON project_status_history(project_id, changed_at);
+
+-- Start the history of existing projects at their creation
+INSERT INTO project_status_history (project_id, to_status, changed_at)
+SELECT id, COALESCE(status, 'planning'), created_at
+FROM projects p
+WHERE NOT EXISTS (SELECT 1 FROM project_status_history h WHERE h.project_id = p.id);
+
+-- Who may run each transition of the default workflow
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'project:subadmin', 'projects/*', 'transition/start'),
+    ('p', 'project:subadmin', 'projects/*', 'transition/hold'),
+    ('p', 'project:subadmin', 'projects/*', 'transition/resume'),
+    ('p', 'project:admin', 'projects/*', 'transition/complete'),
+    ('p', 'project:admin', 'projects/*', 'transition/cancel'),
+    ('p', 'localadmin', 'projects/*', 'transition/start'),
+    ('p', 'localadmin', 'projects/*', 'transition/hold'),
+    ('p', 'localadmin', 'projects/*', 'transition/resume'),
+    ('p', 'localadmin', 'projects/*', 'transition/complete'),
+    ('p', 'localadmin', 'projects/*', 'transition/cancel'),
+    ('p', 'localadmin', 'projects/*', 'transition/reopen');
+
+COMMENT ON TABLE project_status_history IS 'Project status changes; from_status and transition are NULL for the status a project was created with';
+COMMENT ON COLUMN project_status_history.reason IS 'Reason given for the transition, required for cancel and reopen by default';
@@ -0,0 +1,88 @@
+-- Owners registry and percentage shares of projects
+CREATE TABLE IF NOT EXISTS owners (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_owners_name ON owners (LOWER(name)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_owners_created_at_id ON owners (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS project_owners (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES owners(id) ON DELETE RESTRICT,
+    percentage NUMERIC(5,2) NOT NULL CHECK (percentage > 0 AND percentage <= 100),
+    role TEXT NOT NULL DEFAULT 'co-owner' CHECK (role IN ('primary', 'co-owner')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (project_id, owner_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_owners_owner ON project_owners(owner_id);
+
+-- A project with owners has 1 to 10 of them and their shares add up to 100.
+-- The check is deferred to commit so a reassignment can delete and insert
+-- rows freely within its transaction.
+CREATE OR REPLACE FUNCTION fn_validate_project_owners()
+RETURNS TRIGGER AS $$
+DECLARE
+    target_project UUID;
+    owner_count INT;
+    share_total NUMERIC;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target_project := OLD.project_id;
+    ELSE
+        target_project := NEW.project_id;
+    END IF;
+
+    -- Rows removed along with their project need no check
+    IF NOT EXISTS (SELECT 1 FROM projects WHERE id = target_project) THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT COUNT(*), COALESCE(SUM(percentage), 0)
+    INTO owner_count, share_total
+    FROM project_owners
+    WHERE project_id = target_project;
+
+    IF owner_count = 0 THEN
+        RAISE EXCEPTION 'project % must have at least one owner', target_project
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'project_owners_shares';
+    END IF;
+    IF owner_count > 10 THEN
+        RAISE EXCEPTION 'project % cannot have more than 10 owners', target_project
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'project_owners_shares';
+    END IF;
+    IF share_total <> 100 THEN
+        RAISE EXCEPTION 'owner percentages of project % add up to %, not 100', target_project, share_total
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'project_owners_shares';
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_validate_project_owners ON project_owners;
+CREATE CONSTRAINT TRIGGER trg_validate_project_owners
+    AFTER INSERT OR UPDATE OR DELETE ON project_owners
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION fn_validate_project_owners();
+
+-- Any user may look up and register owners; changing or removing them is
+-- limited to localadmin and above. Assigning owners to a project is a
+-- project update.
+INSERT INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'user', 'owners', 'read'),
+    ('p', 'user', 'owners', 'create'),
+    ('p', 'user', 'owners/*', 'read'),
+    ('p', 'localadmin', 'owners/*', 'update'),
+    ('p', 'localadmin', 'owners/*', 'delete');
+
+COMMENT ON TABLE owners IS 'Registry of project and house owners';
+COMMENT ON TABLE project_owners IS 'Percentage shares of projects; 1-10 owners per project adding up to 100, checked at commit';
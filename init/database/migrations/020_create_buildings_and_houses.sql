@@ -0,0 +1,98 @@
+-- Apartment buildings, houses and units of projects, and owner shares of houses
+
+CREATE TABLE IF NOT EXISTS apartment_buildings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    address TEXT,
+    floors INT CHECK (floors > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_apartment_buildings_project ON apartment_buildings(project_id) WHERE deleted_at IS NULL;
+
+-- A house with a building_id is a unit of that building
+CREATE TABLE IF NOT EXISTS houses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    building_id UUID REFERENCES apartment_buildings(id) ON DELETE CASCADE,
+    unit_number TEXT,
+    name TEXT NOT NULL,
+    category_type TEXT,
+    size_sqm NUMERIC(10,2) CHECK (size_sqm > 0),
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT houses_unit_fields CHECK (
+        building_id IS NULL
+        OR (unit_number IS NOT NULL AND category_type IS NOT NULL AND size_sqm IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_houses_project ON houses(project_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_houses_building ON houses(building_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_houses_building_unit ON houses(building_id, unit_number)
+    WHERE building_id IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS house_owners (
+    house_id UUID NOT NULL REFERENCES houses(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES owners(id) ON DELETE RESTRICT,
+    percentage NUMERIC(5,2) NOT NULL CHECK (percentage > 0 AND percentage <= 100),
+    role TEXT NOT NULL DEFAULT 'co-owner' CHECK (role IN ('primary', 'co-owner')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (house_id, owner_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_house_owners_owner ON house_owners(owner_id);
+
+-- A house with owners has at least one and their shares add up to 100.
+-- Like project_owners, the check is deferred to commit.
+CREATE OR REPLACE FUNCTION fn_validate_house_owners()
+RETURNS TRIGGER AS $$
+DECLARE
+    target_house UUID;
+    owner_count INT;
+    share_total NUMERIC;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target_house := OLD.house_id;
+    ELSE
+        target_house := NEW.house_id;
+    END IF;
+
+    -- Rows removed along with their house need no check
+    IF NOT EXISTS (SELECT 1 FROM houses WHERE id = target_house) THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT COUNT(*), COALESCE(SUM(percentage), 0)
+    INTO owner_count, share_total
+    FROM house_owners
+    WHERE house_id = target_house;
+
+    IF owner_count = 0 THEN
+        RAISE EXCEPTION 'house % must have at least one owner', target_house
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'house_owners_shares';
+    END IF;
+    IF share_total <> 100 THEN
+        RAISE EXCEPTION 'owner percentages of house % add up to %, not 100', target_house, share_total
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'house_owners_shares';
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_validate_house_owners ON house_owners;
+CREATE CONSTRAINT TRIGGER trg_validate_house_owners
+    AFTER INSERT OR UPDATE OR DELETE ON house_owners
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION fn_validate_house_owners();
+
+COMMENT ON TABLE apartment_buildings IS 'Apartment buildings of projects';
+COMMENT ON TABLE houses IS 'Standalone houses of a project, or units of an apartment building when building_id is set';
+COMMENT ON TABLE house_owners IS 'Percentage shares of houses; at least one owner adding up to 100, checked at commit';
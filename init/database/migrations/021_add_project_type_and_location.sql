@@ -0,0 +1,29 @@
+-- Project type and coordinates for "near me" and map searches, and the
+-- same fields in archived versions. Distances are computed in SQL, so
+-- PostGIS is not required.
+
+-- The kind of development decides whether a project may have buildings
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS type VARCHAR(20)
+    CHECK (type IN ('Colony', 'IndependentHouse', 'LinkHouse', 'HousingEstate'));
+
+CREATE INDEX IF NOT EXISTS idx_projects_type ON projects(type) WHERE deleted_at IS NULL;
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_coordinates_pair;
+ALTER TABLE projects ADD CONSTRAINT projects_coordinates_pair
+    CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Radius and bounding-box searches narrow by latitude first
+CREATE INDEX IF NOT EXISTS idx_projects_location ON projects(latitude, longitude)
+    WHERE deleted_at IS NULL AND latitude IS NOT NULL;
+
+ALTER TABLE project_versions
+    ADD COLUMN IF NOT EXISTS type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+COMMENT ON COLUMN projects.type IS 'Colony, IndependentHouse, LinkHouse or HousingEstate';
+COMMENT ON TABLE apartment_buildings IS 'Apartment buildings of Colony and HousingEstate projects';
@@ -0,0 +1,40 @@
+-- Files attached to projects. The contents live in the configured blob
+-- store under storage_key; this table holds their metadata.
+CREATE TABLE IF NOT EXISTS project_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    checksum_sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_attachments_project ON project_attachments(project_id, created_at DESC);
+
+-- Blobs whose attachment row is gone, including rows removed when a project
+-- is purged. A background job deletes them from the blob store.
+CREATE TABLE IF NOT EXISTS attachment_blob_deletions (
+    storage_key TEXT PRIMARY KEY,
+    queued_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION fn_queue_attachment_blob_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key)
+    VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_queue_attachment_blob_deletion ON project_attachments;
+CREATE TRIGGER trg_queue_attachment_blob_deletion
+    AFTER DELETE ON project_attachments
+    FOR EACH ROW EXECUTE FUNCTION fn_queue_attachment_blob_deletion();
+
+COMMENT ON TABLE project_attachments IS 'Metadata of files attached to projects; contents are in the blob store';
+COMMENT ON TABLE attachment_blob_deletions IS 'Storage keys of deleted attachments still to be removed from the blob store';
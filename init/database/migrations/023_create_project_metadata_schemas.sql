@@ -0,0 +1,17 @@
+-- JSON Schemas for project metadata, one per project type. Projects are
+-- checked against the schema of their type when their metadata or type
+-- changes; existing metadata is not revalidated when a schema changes.
+CREATE TABLE IF NOT EXISTS project_metadata_schemas (
+    project_type VARCHAR(20) PRIMARY KEY
+        CHECK (project_type IN ('Colony', 'IndependentHouse', 'LinkHouse', 'HousingEstate')),
+    schema JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Equality filters on schema-defined metadata fields use containment (@>)
+CREATE INDEX IF NOT EXISTS idx_projects_metadata ON projects USING GIN (metadata jsonb_path_ops)
+    WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE project_metadata_schemas IS 'JSON Schema that project metadata must satisfy, per project type';
@@ -0,0 +1,27 @@
+-- Tie impersonation sessions to the administrator's own session, so that
+-- revoking it ends every impersonation started from it
+
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS actor_session_id UUID REFERENCES user_sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_actor_session_id ON user_sessions(actor_session_id)
+    WHERE actor_session_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION fn_revoke_impersonation_sessions()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE user_sessions
+    SET revoked_at = NEW.revoked_at
+    WHERE actor_session_id = NEW.id AND revoked_at IS NULL;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_revoke_impersonation_sessions ON user_sessions;
+CREATE TRIGGER trg_revoke_impersonation_sessions
+    AFTER UPDATE OF revoked_at ON user_sessions
+    FOR EACH ROW
+    WHEN (OLD.revoked_at IS NULL AND NEW.revoked_at IS NOT NULL)
+    EXECUTE FUNCTION fn_revoke_impersonation_sessions();
+
+COMMENT ON COLUMN user_sessions.actor_session_id IS 'Session of the administrator who started this impersonation session; revoking it revokes this one.';
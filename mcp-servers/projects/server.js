@@ -315,10 +315,11 @@ class ProjectsMCPServer {
         'Authorization': `Bearer ${token}`
       }
     });
+    const projects = response.items || [];
 
-    logger.info('Projects retrieved', { count: response.length });
+    logger.info('Projects retrieved', { count: projects.length });
     
-    if (response.length === 0) {
+    if (projects.length === 0) {
       return {
         content: [
           {
@@ -329,7 +330,7 @@ class ProjectsMCPServer {
       };
     }
 
-    const projectsList = response.map(project => {
+    const projectsList = projects.map(project => {
       const statusEmoji = {
         'planning': '📋',
         'active': '🚧',
@@ -351,7 +352,7 @@ class ProjectsMCPServer {
       content: [
         {
           type: 'text',
-          text: `Found ${response.length} project(s):\n\n${projectsList}`
+          text: `Found ${projects.length} project(s):\n\n${projectsList}`
         }
       ]
     };